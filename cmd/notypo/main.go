@@ -0,0 +1,214 @@
+// Command notypo is a native client to the notypo-game. It runs a typing
+// speed-test in the terminal, using the same comparison-logic and backend-api
+// as the browser-client
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/streams"
+)
+
+// lookahead is the amount of model-Characters displayed ahead of the cursor
+const lookahead = 40
+
+var (
+	backend    = flag.String("backend", config.Backend.BaseURL.String(), "base-url of the notypo-backend")
+	characters = flag.Int("characters", 0, "end the test after the given amount of characters; zero means no limit")
+	charset    = flag.String("charset", "asdfghjkl ", "characters contained in the model-text")
+	duration   = flag.Duration("duration", time.Minute, "duration of the test, starting with the first keystroke; zero means no limit")
+	firstError = flag.Bool("first-error", false, "end the test with the first miss")
+	offline    = flag.Bool("offline", false, "generate the model-text locally instead of requesting it from the backend")
+	timeout    = flag.Duration("timeout", config.Backend.Timeout, "time limit for each request to the backend")
+	words      = flag.Bool("words", false, "use words from a dictionary instead of random characters")
+)
+
+func main() {
+	flag.Parse()
+	u, err := url.Parse(*backend)
+	if err != nil {
+		fail(err)
+	}
+	config.Backend.BaseURL = u
+	config.Backend.Timeout = *timeout
+
+	description := &config.Description{}
+	description.Type = api.Random
+	for _, r := range *charset {
+		description.Charset = append(description.Charset, api.BasicCharacter(r))
+	}
+
+	if *words {
+		description.Type = api.Dictionary
+	}
+	description.End = config.EndCondition{
+		Duration:   *duration,
+		Characters: *characters,
+		FirstError: *firstError,
+	}
+
+	var model <-chan comparison.Character
+	var closeModel func()
+	if *offline {
+		model, closeModel = localModel(description)
+	} else {
+		model, closeModel = remoteModel(description)
+	}
+	defer closeModel()
+
+	t, err := openTerminal()
+	if err != nil {
+		fail(err)
+	}
+	defer t.restore()
+
+	s, elapsed := play(t, description, model)
+	t.restore()
+	printResults(s, elapsed)
+}
+
+// play runs a single game in the terminal t and returns the final Statistics
+// as well as the time passed since the first keystroke
+func play(t *terminal, description *config.Description, model <-chan comparison.Character) (comparison.Statistics, time.Duration) {
+	v := &view{}
+	var mutex sync.Mutex
+
+	mCopy := make(chan comparison.Character, lookahead)
+	go func() {
+		for c := range model {
+			mutex.Lock()
+			v.model = append(v.model, c.Rune())
+			mutex.Unlock()
+			mCopy <- c
+		}
+		close(mCopy)
+	}()
+
+	attempt := make(chan comparison.Character)
+	done := make(chan bool)
+	var stop sync.Once
+	go func() {
+		allowed := append(append([]api.BasicCharacter{}, description.Charset...), comparison.BS)
+		sort.Slice(allowed, func(i, j int) bool {
+			return allowed[i] < allowed[j]
+		})
+		started := false
+		for {
+			r, quit, err := t.readKey()
+			if err != nil || quit {
+				stop.Do(func() { close(done) })
+				break
+			}
+			i := sort.Search(len(allowed), func(i int) bool {
+				return allowed[i] >= api.BasicCharacter(r)
+			})
+			if i == len(allowed) || allowed[i] != api.BasicCharacter(r) {
+				continue
+			}
+			if !started {
+				started = true
+				mutex.Lock()
+				v.start = time.Now()
+				if description.End.Duration > 0 {
+					v.end = v.start.Add(description.End.Duration)
+					time.AfterFunc(description.End.Duration, func() {
+						stop.Do(func() { close(done) })
+					})
+				}
+				mutex.Unlock()
+			}
+			select {
+			case attempt <- api.BasicCharacter(r):
+			case <-done:
+			}
+		}
+	}()
+	go func() {
+		<-done
+		close(attempt)
+	}()
+
+	cmp := make(chan comparison.Comparison)
+	go comparison.Compare(mCopy, attempt, cmp)
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		mutex.Lock()
+		t.render(v, time.Now())
+		mutex.Unlock()
+		select {
+		case c, ok := <-cmp:
+			if !ok {
+				elapsed := time.Since(v.start)
+				if d := description.End.Duration; d > 0 && elapsed > d {
+					elapsed = d
+				}
+				return v.stats, elapsed
+			}
+			mutex.Lock()
+			v.apply(c)
+			mutex.Unlock()
+			if description.End.Reached(c.Statistics()) {
+				stop.Do(func() { close(done) })
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func remoteModel(description *config.Description) (<-chan comparison.Character, func()) {
+	client := config.Backend.API()
+	ctx, cancel := context.WithCancel(context.Background())
+	streamID, err := client.CreateRandomStream(ctx, &description.StreamSupplierDescription)
+	if err != nil {
+		fail(err)
+	}
+	// lost connections are reestablished silently, as the terminal is
+	// occupied by the game
+	mod, err := client.ReadStream(ctx, 50, *streamID, nil)
+	if err != nil {
+		fail(err)
+	}
+	return mod, cancel
+}
+
+func localModel(description *config.Description) (<-chan comparison.Character, func()) {
+	done := make(chan bool)
+	mod, err := streams.Open(description, 50, done)
+	if err != nil {
+		fail(err)
+	}
+	return mod, func() {
+		close(done)
+	}
+}
+
+func printResults(s comparison.Statistics, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	r := comparison.RatesFor(s, elapsed)
+	fmt.Printf("\n\n")
+	fmt.Printf("net words per minute:   %6.0f\n", r.NetWPM())
+	fmt.Printf("gross words per minute: %6.0f\n", r.GrossWPM())
+	fmt.Printf("characters per minute:  %6.0f\n", r.CPM())
+	fmt.Printf("accuracy:               %6.2f%%\n", 100*r.Accuracy())
+	fmt.Printf("mean latency:           %6dms\n", s.MeanLatency()/time.Millisecond)
+	fmt.Printf("longest pause:          %6dms\n", s.LongestPause()/time.Millisecond)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "notypo:", err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+// ANSI escape-sequences
+const (
+	clearLine = "\r\033[2K"
+	lineUp    = "\033[1A"
+	correct   = "\033[32m"
+	wrong     = "\033[41;37m"
+	upcoming  = "\033[2m"
+	reset     = "\033[0m"
+)
+
+// history is the amount of typed Characters displayed behind the cursor
+const history = 30
+
+// terminal is a terminal in raw mode, that reads single keystrokes from stdin
+type terminal struct {
+	in    *bufio.Reader
+	state string
+}
+
+// view holds everything rendered to the terminal
+type view struct {
+	model []rune
+	typed []bool
+	stats comparison.Statistics
+	start time.Time
+	end   time.Time
+}
+
+// openTerminal switches the terminal attached to stdin to raw mode. The
+// original state is recovered by calling restore
+func openTerminal() (*terminal, error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	_, err = stty("raw", "-echo")
+	if err != nil {
+		return nil, err
+	}
+	return &terminal{
+		in:    bufio.NewReader(os.Stdin),
+		state: state,
+	}, nil
+}
+
+// restore recovers the terminal's original state. It may be called multiple
+// times
+func (t *terminal) restore() {
+	if t.state != "" {
+		stty(t.state)
+		t.state = ""
+	}
+}
+
+// readKey blocks until the next keystroke is available and returns the typed
+// rune. Delete and backspace are both mapped to comparison.BS. quit is true,
+// if the user pressed a lone escape or ctrl+c. Escape-sequences sent by arrow-
+// and function-keys are ignored
+func (t *terminal) readKey() (r rune, quit bool, err error) {
+	for {
+		r, _, err = t.in.ReadRune()
+		if err != nil {
+			return 0, false, err
+		}
+		switch r {
+		case 3:
+			return r, true, nil
+		case 27:
+			// a sequence arrives at once, so a lone escape leaves nothing
+			// buffered
+			if t.in.Buffered() == 0 {
+				return r, true, nil
+			}
+			if err = t.skipSequence(); err != nil {
+				return 0, false, err
+			}
+			continue
+		case 8, 127:
+			return comparison.BS.Rune(), false, nil
+		}
+		return r, false, nil
+	}
+}
+
+// skipSequence discards the rest of an escape-sequence, whose leading escape
+// was read already. CSI-sequences (ESC [ ...) end with a byte in the range
+// 0x40 to 0x7e. All other sequences consist of one more byte
+func (t *terminal) skipSequence() error {
+	b, err := t.in.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b == 'O' && t.in.Buffered() > 0 {
+		_, err = t.in.ReadByte()
+		return err
+	}
+	if b != '[' {
+		return nil
+	}
+	for t.in.Buffered() > 0 {
+		b, err = t.in.ReadByte()
+		if err != nil || (b >= 0x40 && b <= 0x7e) {
+			return err
+		}
+	}
+	return nil
+}
+
+// render draws the model-text and the current statistics. The cursor is
+// placed at the position of the next expected Character
+func (t *terminal) render(v *view, now time.Time) {
+	var b strings.Builder
+	b.WriteString(clearLine)
+	from := len(v.typed) - history
+	if from < 0 {
+		from = 0
+		b.WriteString(strings.Repeat(" ", history-len(v.typed)))
+	}
+	for i := from; i < len(v.typed) && i < len(v.model); i++ {
+		if v.typed[i] {
+			b.WriteString(correct)
+		} else {
+			b.WriteString(wrong)
+		}
+		b.WriteRune(v.model[i])
+		b.WriteString(reset)
+	}
+	b.WriteString(upcoming)
+	for i := len(v.typed); i < len(v.model) && i < len(v.typed)+lookahead; i++ {
+		b.WriteRune(v.model[i])
+	}
+	b.WriteString(reset)
+
+	b.WriteString("\r\n" + clearLine)
+	if v.stats != nil {
+		r := comparison.RatesAt(v.stats, now)
+		fmt.Fprintf(&b, "%4.0f characters per minute | %3.0f words per minute | %5.2f%% failure rate",
+			r.CPM(), r.NetWPM(), 100*v.stats.FailureRate())
+		if !v.end.IsZero() {
+			left := v.end.Sub(now)
+			if left < 0 {
+				left = 0
+			}
+			fmt.Fprintf(&b, " | %3.0fs left", left.Seconds())
+		}
+	} else {
+		b.WriteString("start typing... (esc to quit)")
+	}
+	b.WriteString(lineUp + "\r")
+	fmt.Fprintf(&b, "\033[%dC", history)
+	os.Stdout.WriteString(b.String())
+}
+
+// apply updates the view according to the given Comparison
+func (v *view) apply(c comparison.Comparison) {
+	for _, m := range c.Changes() {
+		if m.Deletion() {
+			if len(v.typed) > 0 {
+				v.typed = v.typed[:len(v.typed)-1]
+			}
+		} else {
+			v.typed = append(v.typed, c.State().Correct())
+		}
+	}
+	v.stats = c.Statistics()
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+func TestTerminalReadKey(t *testing.T) {
+	// arrow-keys, F5, alt+x and a lone escape
+	term := &terminal{in: bufio.NewReader(strings.NewReader("a\033[Ab\033[15~\033OPc\033x\177\033"))}
+	var typed []rune
+	for {
+		r, quit, err := term.readKey()
+		assert.Nil(t, err)
+		if quit {
+			assert.Equal(t, rune(27), r)
+			break
+		}
+		typed = append(typed, r)
+	}
+	assert.Equal(t, []rune{'a', 'b', 'c', comparison.BS.Rune()}, typed)
+}
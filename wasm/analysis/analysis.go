@@ -0,0 +1,100 @@
+// Package analysis aggregates the keystrokes of games per expected character
+// and per finger, so weak spots can be identified
+package analysis
+
+import (
+	"time"
+
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+)
+
+// Stats summarizes the keystrokes, which were expected to produce a certain
+// character, or which were expected to be typed with a certain finger
+type Stats struct {
+	Strokes      int           `json:"strokes"`
+	Misses       int           `json:"misses"`
+	TotalLatency time.Duration `json:"totalLatency"`
+}
+
+// MissRate returns the Misses per Strokes
+func (s Stats) MissRate() float64 {
+	if s.Strokes == 0 {
+		return 0
+	}
+	return float64(s.Misses) / float64(s.Strokes)
+}
+
+// MeanLatency returns the average time passed before the Strokes
+func (s Stats) MeanLatency() time.Duration {
+	if s.Strokes == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Strokes)
+}
+
+// Add returns the sum of s and o
+func (s Stats) Add(o Stats) Stats {
+	return Stats{
+		Strokes:      s.Strokes + o.Strokes,
+		Misses:       s.Misses + o.Misses,
+		TotalLatency: s.TotalLatency + o.TotalLatency,
+	}
+}
+
+// Analysis aggregates Modifications per expected character. It is not safe for
+// concurrent use
+type Analysis struct {
+	characters map[rune]Stats
+}
+
+// New creates an empty Analysis
+func New() *Analysis {
+	return &Analysis{
+		characters: make(map[rune]Stats),
+	}
+}
+
+// Add includes the given Modification. Deletions are ignored. The first
+// keystroke's latency is zero, so it doesn't distort the MeanLatency
+func (a *Analysis) Add(m comparison.Modification) {
+	if m.Deletion() || m.Expected() == nil {
+		return
+	}
+	s := Stats{
+		Strokes:      1,
+		TotalLatency: m.Latency(),
+	}
+	if !m.Correct() {
+		s.Misses = 1
+	}
+	r := m.Expected().Rune()
+	a.characters[r] = a.characters[r].Add(s)
+}
+
+// Character returns the Stats of all keystrokes, which were expected to produce
+// the given rune
+func (a *Analysis) Character(r rune) Stats {
+	return a.characters[r]
+}
+
+// Characters returns the Stats per expected rune
+func (a *Analysis) Characters() map[rune]Stats {
+	c := make(map[rune]Stats, len(a.characters))
+	for r, s := range a.characters {
+		c[r] = s
+	}
+	return c
+}
+
+// Fingers returns the Stats per Finger according to the given Layout.
+// Characters, which are not on the Layout, are ignored
+func (a *Analysis) Fingers(l *layout.Layout) map[layout.Finger]Stats {
+	fingers := make(map[layout.Finger]Stats)
+	for r, s := range a.characters {
+		if f, ok := l.Finger(r); ok {
+			fingers[f] = fingers[f].Add(s)
+		}
+	}
+	return fingers
+}
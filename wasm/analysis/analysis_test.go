@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+)
+
+type timedchar struct {
+	api.BasicCharacter
+	time time.Time
+}
+
+func (c timedchar) Time() time.Time {
+	return c.time
+}
+
+// analyze compares attempt to model, where the i-th attempt-rune is typed
+// offsets[i] milliseconds after the first one
+func analyze(model, attempt string, offsets ...int) *Analysis {
+	m := make(chan comparison.Character, len(model))
+	for _, r := range model {
+		m <- api.BasicCharacter(r)
+	}
+	start := time.Now()
+	a := make(chan comparison.Character, len(attempt))
+	for i, r := range []rune(attempt) {
+		a <- timedchar{api.BasicCharacter(r), start.Add(time.Duration(offsets[i]) * time.Millisecond)}
+	}
+	close(a)
+	cmp := make(chan comparison.Comparison)
+	go comparison.Compare(m, a, cmp)
+	an := New()
+	for c := range cmp {
+		for _, change := range c.Changes() {
+			an.Add(change)
+		}
+	}
+	return an
+}
+
+func TestCharacters(t *testing.T) {
+	a := analyze("asa", "ax\bsa", 0, 100, 200, 500, 600)
+	assert.Equal(t, Stats{Strokes: 2, Misses: 1, TotalLatency: 400 * time.Millisecond}, a.Character('s'))
+	assert.Equal(t, 0.5, a.Character('s').MissRate())
+	assert.Equal(t, 200*time.Millisecond, a.Character('s').MeanLatency())
+	assert.Equal(t, Stats{Strokes: 2, TotalLatency: 100 * time.Millisecond}, a.Character('a'))
+	assert.Equal(t, 2, len(a.Characters()))
+	assert.Equal(t, Stats{}, a.Character('x'))
+	assert.Equal(t, float64(0), Stats{}.MissRate())
+	assert.Equal(t, time.Duration(0), Stats{}.MeanLatency())
+}
+
+func TestFingers(t *testing.T) {
+	a := analyze("aSw ;", "aXw ;", 0, 100, 200, 300, 400)
+	fingers := a.Fingers(layout.US)
+	assert.Equal(t, Stats{Strokes: 1}, fingers[layout.LeftLittle])
+	assert.Equal(t, Stats{Strokes: 2, Misses: 1, TotalLatency: 200 * time.Millisecond}, fingers[layout.LeftRing])
+	assert.Equal(t, 1, fingers[layout.Thumbs].Strokes)
+	assert.Equal(t, 1, fingers[layout.RightLittle].Strokes)
+	assert.Equal(t, 4, len(fingers))
+}
+
+func TestWeights(t *testing.T) {
+	stats := map[rune]Stats{
+		'a': {Strokes: 10, TotalLatency: 2 * time.Second},
+		'b': {Strokes: 10, Misses: 5, TotalLatency: 2 * time.Second},
+		'c': {Strokes: 10, TotalLatency: 4 * time.Second},
+		'd': {Strokes: 10, Misses: 10, TotalLatency: 8 * time.Second},
+		'x': {Strokes: 10, Misses: 10},
+	}
+	w := Weights(stats, []rune("abcde"))
+	assert.Equal(t, 5, len(w))
+	// the average latency is 400ms
+	assert.InDelta(t, 1, w['a'], 0.001)
+	assert.InDelta(t, 3, w['b'], 0.001)
+	assert.InDelta(t, 1, w['c'], 0.001)
+	assert.InDelta(t, MaxWeight, w['d'], 0.001)
+	// characters never typed have the default weight
+	assert.InDelta(t, 1, w['e'], 0.001)
+}
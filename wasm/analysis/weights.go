@@ -0,0 +1,40 @@
+package analysis
+
+// weighting
+var (
+	// MissWeight is the additional weight of a character, which is always
+	// missed
+	MissWeight = 4.0
+	// MaxWeight limits the weight of a single character
+	MaxWeight = 5.0
+)
+
+// Weights returns a weight for each of the given runes, which determines how
+// often it should be practiced compared to the others. Runes, which were
+// typed without misses at average speed, or which were never typed, have
+// weight 1. The weight grows with the rune's miss rate and with its mean
+// latency exceeding the average latency of all given runes
+func Weights(stats map[rune]Stats, charset []rune) map[rune]float64 {
+	var total Stats
+	for _, r := range charset {
+		total = total.Add(stats[r])
+	}
+	average := total.MeanLatency()
+
+	weights := make(map[rune]float64, len(charset))
+	for _, r := range charset {
+		s := stats[r]
+		w := 1.0
+		if s.Strokes > 0 {
+			w += MissWeight * s.MissRate()
+			if average > 0 && s.MeanLatency() > average {
+				w += float64(s.MeanLatency()-average) / float64(average)
+			}
+		}
+		if w > MaxWeight {
+			w = MaxWeight
+		}
+		weights[r] = w
+	}
+	return weights
+}
@@ -0,0 +1,38 @@
+package communication
+
+import (
+	"context"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Backend is the backend-api, as far as it is used by the game. The Client
+// talks to the actual backend, while communicationtest.Fake is an in-memory
+// replacement for tests. Each call is bound to the given context.Context
+type Backend interface {
+	// Version requests the backend-api's version-information
+	Version(ctx context.Context) (*api.VersionResponse, errors.Error)
+	// StreamOptions requests the available StreamTypes
+	StreamOptions(ctx context.Context) (api.StreamOptionsResponse, errors.Error)
+	// CreateRandomStream creates a Stream based on the given description and
+	// returns its StreamID
+	CreateRandomStream(ctx context.Context, description *api.StreamSupplierDescription) (*int64, errors.Error)
+	// OpenStreamConnection opens a connection to the given Stream and
+	// returns its StreamConnectionID
+	OpenStreamConnection(ctx context.Context, streamID int64) (*int64, errors.Error)
+	// ReadStreamConnection returns the content of the connection with the
+	// given id. The returned channel is closed, when the connection is lost
+	// or ctx is done
+	ReadStreamConnection(ctx context.Context, buffer uint, streamConnectionID int64) (<-chan comparison.Character, errors.Error)
+	// CloseStreamConnection closes the connection with the given id
+	CloseStreamConnection(ctx context.Context, streamConnectionID int64) errors.Error
+	// ReadStream returns the content of the Stream with the given id. Lost
+	// connections are reestablished, while warn is notified about it. The
+	// returned channel is closed, when the Stream can't be resumed or ctx is
+	// done
+	ReadStream(ctx context.Context, buffer uint, streamID int64, warn func(errors.Error)) (<-chan comparison.Character, errors.Error)
+}
+
+var _ Backend = (*Client)(nil)
@@ -2,6 +2,7 @@ package communication
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net"
 	"net/http"
@@ -9,7 +10,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/gopherjs/websocket"
 	"github.com/theMomax/notypo-backend/api"
 	"github.com/theMomax/notypo-frontend/wasm/comparison"
 	"github.com/theMomax/notypo-frontend/wasm/errors"
@@ -24,19 +24,86 @@ var (
 	ErrStreamNotImplemented     = errors.New("the server doesn't know the reqested stream-type", errors.Critical, errors.Server)
 	ErrStreamNotFound           = errors.New("the server couldn't find a stream with the given id", errors.Critical, errors.Server)
 	ErrIllegalConfiguration     = errors.New("the configuration is not valid for this type of stream", errors.Critical)
+	ErrCanceled                 = errors.New("the request was canceled", errors.Warning)
+	ErrStreamConnectionLost     = errors.New("the connection to the stream was lost", errors.Warning, errors.Server)
+	ErrReconnecting             = errors.New("the connection to the stream was lost, reconnecting", errors.Warning, errors.Server)
 )
 
-// Version requests the backend-api's version-information
-func Version(baseURL *url.URL) (*api.VersionResponse, errors.Error) {
-	resp, err := http.Get(baseURL.String() + api.PathVersion)
+// Client talks to the backend-api at BaseURL. Each call is bound to the
+// given context.Context, so it can be canceled, e.g. when the user leaves the
+// game. Timeouts are configured via the underlying http.Client. All methods
+// are safe for concurrent use
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	// Backoff configures how streams read using ReadStream are reconnected.
+	// It must not be changed, while the Client is in use
+	Backoff Backoff
+}
+
+// NewClient creates a Client for the backend-api at the given base URL. If
+// httpClient is nil, http.DefaultClient is used
+func NewClient(baseURL *url.URL, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL: baseURL,
+		http:    httpClient,
+		Backoff: DefaultBackoff,
+	}
+}
+
+// BaseURL returns the backend-api's base URL
+func (c *Client) BaseURL() *url.URL {
+	return c.baseURL
+}
+
+// do sends a request with the given method, path and body
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, errors.Error) {
+	req, err := http.NewRequest(method, c.baseURL.String()+path, bytes.NewReader(body))
 	if err != nil {
-		return nil, ErrServerConnectionFailed.Append(err.Error())
+		return nil, ErrUnexpectedArgumentFormat.Append(err.Error())
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, failed(ctx, err)
+	}
+	return resp, nil
+}
+
+// failed converts an error, which occurred while communicating with the
+// backend. Canceled requests aren't reported as a connection-failure
+func failed(ctx context.Context, err error) errors.Error {
+	if ctx.Err() == context.Canceled {
+		return ErrCanceled
+	}
+	return ErrServerConnectionFailed.Append(err.Error())
+}
+
+// websocketURL returns the URL of the backend's websocket with the given path
+func (c *Client) websocketURL(path string) string {
+	u := url.URL{Scheme: "ws", Host: c.baseURL.Host, Path: path}
+	if c.baseURL.Scheme == "https" {
+		u.Scheme = "wss"
+	}
+	return u.String()
+}
+
+// Version requests the backend-api's version-information
+func (c *Client) Version(ctx context.Context) (*api.VersionResponse, errors.Error) {
+	resp, e := c.do(ctx, "GET", api.PathVersion, "", nil)
+	if e != nil {
+		return nil, e
 	}
 	defer resp.Body.Close()
 	switch resp.StatusCode {
 	case 200:
 		var v api.VersionResponse
-		err = json.NewDecoder(resp.Body).Decode(&v)
+		err := json.NewDecoder(resp.Body).Decode(&v)
 		if err != nil {
 			return nil, ErrUnexpectedResponseFormat.Append(err.Error())
 		}
@@ -49,17 +116,16 @@ func Version(baseURL *url.URL) (*api.VersionResponse, errors.Error) {
 }
 
 // StreamOptions requests the available StreamTypes
-func StreamOptions(baseURL *url.URL) (api.StreamOptionsResponse, errors.Error) {
-	req, err := http.NewRequest("GET", baseURL.String()+api.PathStreamOptions, nil)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, ErrServerConnectionFailed.Append(err.Error())
+func (c *Client) StreamOptions(ctx context.Context) (api.StreamOptionsResponse, errors.Error) {
+	resp, e := c.do(ctx, "GET", api.PathStreamOptions, "", nil)
+	if e != nil {
+		return nil, e
 	}
 	defer resp.Body.Close()
 	switch resp.StatusCode {
 	case 200:
 		var s api.StreamOptionsResponse
-		err = json.NewDecoder(resp.Body).Decode(&s)
+		err := json.NewDecoder(resp.Body).Decode(&s)
 		if err != nil {
 			return nil, ErrUnexpectedResponseFormat.Append(err.Error())
 		}
@@ -71,20 +137,20 @@ func StreamOptions(baseURL *url.URL) (api.StreamOptionsResponse, errors.Error) {
 
 // CreateRandomStream creates a Stream based on the given description and
 // returns its StreamID
-func CreateRandomStream(baseURL *url.URL, description *api.StreamSupplierDescription) (*int64, errors.Error) {
+func (c *Client) CreateRandomStream(ctx context.Context, description *api.StreamSupplierDescription) (*int64, errors.Error) {
 	b, err := json.Marshal(description)
 	if err != nil {
 		return nil, ErrUnexpectedArgumentFormat.Append(err.Error())
 	}
-	resp, err := http.Post(baseURL.String()+api.PathCreateStream, "application/json", bytes.NewReader(b))
-	if err != nil {
-		return nil, ErrServerConnectionFailed.Append(err.Error())
+	resp, e := c.do(ctx, "POST", api.PathCreateStream, "application/json", b)
+	if e != nil {
+		return nil, e
 	}
 	defer resp.Body.Close()
 	switch resp.StatusCode {
 	case 200:
 		var streamID int64
-		json.NewDecoder(resp.Body).Decode(&streamID)
+		err = json.NewDecoder(resp.Body).Decode(&streamID)
 		if err != nil {
 			return nil, ErrUnexpectedResponseFormat.Append(err.Error())
 		}
@@ -100,16 +166,16 @@ func CreateRandomStream(baseURL *url.URL, description *api.StreamSupplierDescrip
 
 // OpenStreamConnection opens a connection to the given Stream and returns its
 // StreamConnectionID
-func OpenStreamConnection(baseURL *url.URL, streamID int64) (*int64, errors.Error) {
-	resp, err := http.Get(baseURL.String() + strings.TrimSuffix(api.PathOpenStreamConnection, "{id}") + strconv.FormatInt(streamID, 10))
-	if err != nil {
-		return nil, ErrServerConnectionFailed.Append(err.Error())
+func (c *Client) OpenStreamConnection(ctx context.Context, streamID int64) (*int64, errors.Error) {
+	resp, e := c.do(ctx, "GET", strings.TrimSuffix(api.PathOpenStreamConnection, "{id}")+strconv.FormatInt(streamID, 10), "", nil)
+	if e != nil {
+		return nil, e
 	}
 	defer resp.Body.Close()
 	switch resp.StatusCode {
 	case 200:
 		var streamConnectionID int64
-		json.NewDecoder(resp.Body).Decode(&streamConnectionID)
+		err := json.NewDecoder(resp.Body).Decode(&streamConnectionID)
 		if err != nil {
 			return nil, ErrUnexpectedResponseFormat.Append(err.Error())
 		}
@@ -124,91 +190,34 @@ func OpenStreamConnection(baseURL *url.URL, streamID int64) (*int64, errors.Erro
 // ReadStreamConnection returns a channel of Characters with the given buffer
 // and spawns a new goroutine, which requests the Stream's content and pipes it
 // into the returned channel. This process ends, when the connection is closed
-// by the server, or when the goroutine receives a signal from the given done
-// channel
-func ReadStreamConnection(baseURL *url.URL, buffer uint, streamConnectionID int64, done <-chan bool) (<-chan comparison.Character, errors.Error) {
-	mod := make(chan comparison.Character, int(buffer))
-	var c net.Conn
-	u := url.URL{Scheme: "ws", Host: baseURL.Host, Path: strings.TrimSuffix(api.PathEstablishWebsocketToStream, "{id}") + strconv.FormatInt(streamConnectionID, 10)}
-	c, err := websocket.Dial(u.String())
+// by the server, or when the given context is done
+func (c *Client) ReadStreamConnection(ctx context.Context, buffer uint, streamConnectionID int64) (<-chan comparison.Character, errors.Error) {
+	conn, err := c.dialStreamConnection(ctx, streamConnectionID)
 	if err != nil {
-		return nil, ErrServerConnectionFailed.Append(err.Error())
+		return nil, err
 	}
-
+	mod := make(chan comparison.Character, int(buffer))
 	go func() {
-		charRequests := make(chan uint, 10)
-		reqLimit := buffer / 10
-		if reqLimit == 0 {
-			reqLimit = 1
-		}
-		charRequests <- reqLimit
-		// request characters
-		go func() {
-			reqAmount := uint(0)
-			for {
-				select {
-				case <-done:
-					c.Close()
-					close(mod)
-					close(charRequests)
-					return
-				case n := <-charRequests:
-					reqAmount += n
-					if reqAmount >= reqLimit {
-						b, err := json.Marshal(&reqAmount)
-						if err != nil {
-							c.Close()
-							return
-						}
-						_, err = c.Write(b)
-						if err != nil {
-							c.Close()
-							return
-						}
-						reqAmount = 0
-					}
-				}
-			}
-		}()
-		// process stream
-		done := false
-	outer:
-		for !done {
-			var char api.BasicCharacter
-			b := make([]byte, 100)
-			n, err := c.Read(b)
-			if err != nil {
-				c.Close()
-				break outer
-			}
-			err = json.Unmarshal(b[:n], &char)
-			if err != nil {
-				c.Close()
-				break outer
-			}
-			// triggered, by write on closed channel, when request-routine
-			// receives from done channel and returns instread of second close()
-			defer func() {
-				p := recover()
-				if p != nil {
-					done = true
-				}
-			}()
-			mod <- char
-			charRequests <- 1
-		}
-		close(charRequests)
-		close(mod)
+		defer close(mod)
+		readStream(ctx, conn, buffer, 0, mod)
 	}()
 	return mod, nil
 }
 
-// CloseStreamConnection closes the connection with the given id
-func CloseStreamConnection(baseURL *url.URL, streamConnectionID int64) errors.Error {
-	req, err := http.NewRequest("DELETE", baseURL.String()+strings.TrimSuffix(api.PathCloseStreamConnection, "{id}")+strconv.FormatInt(streamConnectionID, 10), nil)
-	resp, err := http.DefaultClient.Do(req)
+// dialStreamConnection opens the websocket of the connection with the given id
+func (c *Client) dialStreamConnection(ctx context.Context, streamConnectionID int64) (net.Conn, errors.Error) {
+	conn, err := dial(ctx, c.websocketURL(strings.TrimSuffix(api.PathEstablishWebsocketToStream, "{id}")+strconv.FormatInt(streamConnectionID, 10)))
 	if err != nil {
-		return ErrServerConnectionFailed.Append(err.Error())
+		return nil, failed(ctx, err)
+	}
+	return conn, nil
+}
+
+// CloseStreamConnection closes the connection with the given id
+func (c *Client) CloseStreamConnection(ctx context.Context, streamConnectionID int64) errors.Error {
+	resp, e := c.do(ctx, "DELETE", strings.TrimSuffix(api.PathCloseStreamConnection, "{id}")+strconv.FormatInt(streamConnectionID, 10), "", nil)
+	if e != nil {
+		return e
 	}
 	defer resp.Body.Close()
 	switch resp.StatusCode {
@@ -0,0 +1,226 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// backend starts a test-server using the given handler and returns a Client
+// connected to it
+func backend(t *testing.T, handler http.HandlerFunc, httpClient *http.Client) (*Client, func()) {
+	s := httptest.NewServer(handler)
+	u, err := url.Parse(s.URL)
+	assert.Nil(t, err)
+	return NewClient(u, httpClient), s.Close
+}
+
+func TestClientVersion(t *testing.T) {
+	c, stop := backend(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, api.PathVersion, r.URL.Path)
+		json.NewEncoder(w).Encode(&api.VersionResponse{Version: "v1.0.0"})
+	}, nil)
+	defer stop()
+	v, err := c.Version(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.0.0", v.Version)
+}
+
+func TestClientCreateRandomStream(t *testing.T) {
+	c, stop := backend(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		var d api.StreamSupplierDescription
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&d))
+		if d.Type != api.Random {
+			w.WriteHeader(400)
+			return
+		}
+		w.Write([]byte("42"))
+	}, nil)
+	defer stop()
+	id, err := c.CreateRandomStream(context.Background(), &api.StreamSupplierDescription{Type: api.Random})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), *id)
+	_, err = c.CreateRandomStream(context.Background(), &api.StreamSupplierDescription{Type: api.Dictionary})
+	assert.Equal(t, ErrIllegalConfiguration, err)
+}
+
+func TestClientCancel(t *testing.T) {
+	// the backend hangs until the request is canceled
+	c, stop := backend(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, nil)
+	defer stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := c.OpenStreamConnection(ctx, 1)
+	assert.Equal(t, ErrCanceled, err)
+}
+
+func TestClientTimeout(t *testing.T) {
+	c, stop := backend(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, &http.Client{Timeout: 10 * time.Millisecond})
+	defer stop()
+	err := c.CloseStreamConnection(context.Background(), 1)
+	assert.Equal(t, ErrServerConnectionFailed.Type(), err.Type())
+}
+
+func TestClientReadStreamConnection(t *testing.T) {
+	// each Character is sent in its own message, so the numbers are only
+	// delimited by the messages' boundaries
+	messages := []string{`97`, `98`, `"😀"`, `[99,128512]`, `100`}
+	c, stop := backend(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/stream/websocket/7", r.URL.Path)
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer ws.Close()
+		for _, m := range messages {
+			ws.WriteMessage(websocket.TextMessage, []byte(m))
+		}
+		// the requests are ignored until the client closes the connection
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}, nil)
+	defer stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	mod, err := c.ReadStreamConnection(ctx, 10, 7)
+	assert.Nil(t, err)
+	var received []rune
+	for len(received) < 6 {
+		received = append(received, (<-mod).Rune())
+	}
+	assert.Equal(t, "ab😀c😀d", string(received))
+	cancel()
+	assert.Equal(t, "", runes(t, mod))
+}
+
+// flakyStream is a backend serving the Stream "abcdef". Each connection is
+// served by the function at the connection's index, which decides, how much
+// of the Stream is sent. Like the real backend, it deletes the Stream, when
+// its last connection is closed
+type flakyStream struct {
+	mutex       sync.Mutex
+	connections []func(ws *websocket.Conn)
+	opened      int
+	closed      int
+}
+
+func (f *flakyStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "DELETE":
+		f.mutex.Lock()
+		f.closed++
+		f.mutex.Unlock()
+	case strings.HasPrefix(r.URL.Path, "/stream/websocket/"):
+		f.mutex.Lock()
+		serve := f.connections[f.opened-1]
+		f.mutex.Unlock()
+		if serve == nil {
+			w.WriteHeader(404)
+			return
+		}
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		serve(ws)
+	default:
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if f.opened > 0 && f.closed == f.opened {
+			w.WriteHeader(404)
+			return
+		}
+		f.opened++
+		json.NewEncoder(w).Encode(f.opened)
+	}
+}
+
+// send sends the first n Characters of the Stream
+func send(n int) func(ws *websocket.Conn) {
+	return func(ws *websocket.Conn) {
+		for _, r := range "abcdef"[:n] {
+			b, _ := json.Marshal(r)
+			ws.WriteMessage(websocket.TextMessage, b)
+		}
+		// the requests are ignored until the client closes the connection
+		if n == 6 {
+			for {
+				if _, _, err := ws.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readFlakyStream reads the flakyStream served by the given functions and
+// returns the Characters read as well as the warnings
+func readFlakyStream(t *testing.T, connections ...func(ws *websocket.Conn)) (string, []errors.Error) {
+	c, stop := backend(t, (&flakyStream{connections: connections}).ServeHTTP, nil)
+	defer stop()
+	c.Backoff = Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond, Attempts: 3}
+	var mutex sync.Mutex
+	var warnings []errors.Error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mod, err := c.ReadStream(ctx, 10, 1, func(e errors.Error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		warnings = append(warnings, e)
+	})
+	assert.Nil(t, err)
+	var received []rune
+	timeout := time.After(time.Second)
+	for len(received) < 6 {
+		select {
+		case c, ok := <-mod:
+			if !ok {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return string(received), warnings
+			}
+			received = append(received, c.Rune())
+		case <-timeout:
+			t.Fatal("the stream wasn't resumed")
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	return string(received), warnings
+}
+
+func TestClientReadStreamReconnect(t *testing.T) {
+	text, warnings := readFlakyStream(t, send(3), send(1), nil, send(6))
+	assert.Equal(t, "abcdef", text)
+	// the second connection is lost before the stream is resumed
+	assert.Equal(t, []errors.Error{ErrReconnecting, nil, ErrReconnecting, ErrReconnecting, nil}, warnings)
+}
+
+func TestClientReadStreamGiveUp(t *testing.T) {
+	text, warnings := readFlakyStream(t, send(2), nil, send(1), nil, send(6))
+	// the lost connection may be noticed before all of its messages are read
+	assert.True(t, strings.HasPrefix("ab", text))
+	if assert.Len(t, warnings, 5) {
+		assert.Equal(t, []errors.Error{ErrReconnecting, ErrReconnecting, nil, ErrReconnecting}, warnings[:4])
+		assert.Equal(t, ErrServerConnectionFailed.Type(), warnings[4].Type())
+	}
+}
@@ -0,0 +1,327 @@
+// Package communicationtest provides an in-memory communication.Backend for
+// tests
+package communicationtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Call identifies a method of a communication.Backend
+type Call string
+
+// calls
+const (
+	CallVersion               Call = "Version"
+	CallStreamOptions         Call = "StreamOptions"
+	CallCreateRandomStream    Call = "CreateRandomStream"
+	CallOpenStreamConnection  Call = "OpenStreamConnection"
+	CallReadStreamConnection  Call = "ReadStreamConnection"
+	CallCloseStreamConnection Call = "CloseStreamConnection"
+)
+
+// Fake is an in-memory communication.Backend. Like the actual backend, it
+// serves a separate text per Stream and deletes a Stream, when its last
+// connection is closed. The fields must not be changed, while the Fake is in
+// use. All methods are safe for concurrent use
+type Fake struct {
+	// Closed makes the Streams end after their text. Otherwise, they stay
+	// open without emitting any further Characters
+	Closed bool
+	// Latency delays each response as well as each Character
+	Latency time.Duration
+	// Options is returned by StreamOptions
+	Options api.StreamOptionsResponse
+	// DropAfter makes the first connection to each Stream get lost after the
+	// given number of Characters. Zero means never
+	DropAfter int
+
+	generate     func(api.StreamSupplierDescription) string
+	mutex        sync.Mutex
+	failures     map[Call][]errors.Error
+	descriptions []api.StreamSupplierDescription
+	streams      map[int64]*stream
+	connections  map[int64]*connection
+	lastID       int64
+}
+
+// stream is a supplier of the Fake
+type stream struct {
+	text []rune
+	// opened counts all connections ever opened, open the ones not closed yet
+	opened int
+	open   int
+}
+
+type connection struct {
+	streamID int64
+	stream   *stream
+	// drop is the number of Characters, after which the connection is lost
+	drop int
+}
+
+// NewFake creates a Fake, which generates the text of each Stream created by
+// CreateRandomStream from the Stream's description
+func NewFake(generate func(api.StreamSupplierDescription) string) *Fake {
+	return &Fake{
+		generate:    generate,
+		failures:    make(map[Call][]errors.Error),
+		streams:     make(map[int64]*stream),
+		connections: make(map[int64]*connection),
+	}
+}
+
+// Text returns a generator for NewFake, which generates the given text for all
+// Streams
+func Text(text string) func(api.StreamSupplierDescription) string {
+	return func(api.StreamSupplierDescription) string {
+		return text
+	}
+}
+
+// AddStream creates a Stream with the given text, e.g. one created by another
+// player, and returns its id
+func (f *Fake) AddStream(text string) int64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.lastID++
+	f.streams[f.lastID] = &stream{text: []rune(text)}
+	return f.lastID
+}
+
+// Fail makes the next call of the given method fail with err. Several failures
+// of the same method are returned in order
+func (f *Fake) Fail(call Call, err errors.Error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.failures[call] = append(f.failures[call], err)
+}
+
+// Descriptions returns the descriptions of all Streams created by
+// CreateRandomStream so far
+func (f *Fake) Descriptions() []api.StreamSupplierDescription {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]api.StreamSupplierDescription(nil), f.descriptions...)
+}
+
+// Connections returns the number of open connections
+func (f *Fake) Connections() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.connections)
+}
+
+// Version returns a fixed version-information
+func (f *Fake) Version(ctx context.Context) (*api.VersionResponse, errors.Error) {
+	if err := f.respond(ctx, CallVersion); err != nil {
+		return nil, err
+	}
+	return &api.VersionResponse{Version: "fake"}, nil
+}
+
+// StreamOptions returns the Fake's Options
+func (f *Fake) StreamOptions(ctx context.Context) (api.StreamOptionsResponse, errors.Error) {
+	if err := f.respond(ctx, CallStreamOptions); err != nil {
+		return nil, err
+	}
+	return f.Options, nil
+}
+
+// CreateRandomStream records the given description and creates a new Stream,
+// whose text is generated from it
+func (f *Fake) CreateRandomStream(ctx context.Context, description *api.StreamSupplierDescription) (*int64, errors.Error) {
+	if err := f.respond(ctx, CallCreateRandomStream); err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	f.descriptions = append(f.descriptions, *description)
+	f.mutex.Unlock()
+	id := f.AddStream(f.generate(*description))
+	return &id, nil
+}
+
+// OpenStreamConnection opens a new connection to the given Stream
+func (f *Fake) OpenStreamConnection(ctx context.Context, streamID int64) (*int64, errors.Error) {
+	if err := f.respond(ctx, CallOpenStreamConnection); err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	s, ok := f.streams[streamID]
+	if !ok {
+		return nil, com.ErrStreamNotFound
+	}
+	c := &connection{streamID: streamID, stream: s}
+	if s.opened == 0 {
+		c.drop = f.DropAfter
+	}
+	s.opened++
+	s.open++
+	f.lastID++
+	f.connections[f.lastID] = c
+	id := f.lastID
+	return &id, nil
+}
+
+// ReadStreamConnection emits the Stream's text from the beginning
+func (f *Fake) ReadStreamConnection(ctx context.Context, buffer uint, streamConnectionID int64) (<-chan comparison.Character, errors.Error) {
+	if err := f.respond(ctx, CallReadStreamConnection); err != nil {
+		return nil, err
+	}
+	c, err := f.connection(streamConnectionID)
+	if err != nil {
+		return nil, err
+	}
+	mod := make(chan comparison.Character, int(buffer))
+	go func() {
+		defer close(mod)
+		f.emit(ctx, c, 0, mod)
+	}()
+	return mod, nil
+}
+
+// CloseStreamConnection closes the connection with the given id. The Stream
+// is deleted together with its last connection
+func (f *Fake) CloseStreamConnection(ctx context.Context, streamConnectionID int64) errors.Error {
+	if err := f.respond(ctx, CallCloseStreamConnection); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	c, ok := f.connections[streamConnectionID]
+	if !ok {
+		return com.ErrStreamNotFound
+	}
+	delete(f.connections, streamConnectionID)
+	c.stream.open--
+	if c.stream.open == 0 {
+		delete(f.streams, c.streamID)
+	}
+	return nil
+}
+
+// ReadStream reads the given Stream like ReadStreamConnection. A lost
+// connection is replaced by a new one right away, which skips the Characters
+// received before. The lost connection is closed afterwards. warn is called
+// with communication.ErrReconnecting and nil meanwhile
+func (f *Fake) ReadStream(ctx context.Context, buffer uint, streamID int64, warn func(errors.Error)) (<-chan comparison.Character, errors.Error) {
+	if warn == nil {
+		warn = func(errors.Error) {}
+	}
+	open := func() (int64, *connection, errors.Error) {
+		id, err := f.OpenStreamConnection(ctx, streamID)
+		if err != nil {
+			return 0, nil, err
+		}
+		c, err := f.connection(*id)
+		return *id, c, err
+	}
+	id, c, err := open()
+	if err != nil {
+		return nil, err
+	}
+	mod := make(chan comparison.Character, int(buffer))
+	go func() {
+		defer close(mod)
+		offset := 0
+		for {
+			n, lost := f.emit(ctx, c, offset, mod)
+			offset += n
+			if !lost {
+				f.CloseStreamConnection(context.Background(), id)
+				return
+			}
+			warn(com.ErrReconnecting)
+			previous := id
+			id, c, err = open()
+			f.CloseStreamConnection(context.Background(), previous)
+			if err != nil {
+				warn(err)
+				return
+			}
+			warn(nil)
+		}
+	}()
+	return mod, nil
+}
+
+// respond simulates a request of the given method
+func (f *Fake) respond(ctx context.Context, call Call) errors.Error {
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	failures := f.failures[call]
+	if len(failures) == 0 {
+		return nil
+	}
+	f.failures[call] = failures[1:]
+	return failures[0]
+}
+
+// wait waits for the Fake's Latency. If ctx is done before,
+// communication.ErrCanceled is returned
+func (f *Fake) wait(ctx context.Context) errors.Error {
+	if f.Latency == 0 {
+		if ctx.Err() != nil {
+			return com.ErrCanceled
+		}
+		return nil
+	}
+	t := time.NewTimer(f.Latency)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return com.ErrCanceled
+	}
+}
+
+func (f *Fake) connection(streamConnectionID int64) (*connection, errors.Error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	c, ok := f.connections[streamConnectionID]
+	if !ok {
+		return nil, com.ErrStreamNotFound
+	}
+	return c, nil
+}
+
+// emit pipes the connection's text into mod, where the first skip Characters
+// are dropped. It returns the number of Characters piped into mod and whether
+// the connection was lost
+func (f *Fake) emit(ctx context.Context, c *connection, skip int, mod chan<- comparison.Character) (int, bool) {
+	piped := 0
+	for i, r := range c.stream.text {
+		if c.drop > 0 && i == c.drop {
+			return piped, true
+		}
+		if f.wait(ctx) != nil {
+			return piped, false
+		}
+		if i < skip {
+			continue
+		}
+		select {
+		case mod <- api.BasicCharacter(r):
+			piped++
+		case <-ctx.Done():
+			return piped, false
+		}
+	}
+	if !f.Closed {
+		<-ctx.Done()
+	}
+	return piped, false
+}
+
+var _ com.Backend = (*Fake)(nil)
@@ -0,0 +1,108 @@
+package communicationtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+// runes reads the given stream until it is closed
+func runes(t *testing.T, mod <-chan comparison.Character) string {
+	var r []rune
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case c, ok := <-mod:
+			if !ok {
+				return string(r)
+			}
+			r = append(r, c.Rune())
+		case <-timeout:
+			t.Fatal("the stream wasn't closed")
+		}
+	}
+}
+
+// byType generates the name of the description's StreamSourceType
+func byType(d api.StreamSupplierDescription) string {
+	return string(d.Type)
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake(Text("ab😀"))
+	f.Closed = true
+	f.Fail(CallCreateRandomStream, com.ErrStreamNotImplemented)
+	f.Fail(CallCreateRandomStream, com.ErrIllegalConfiguration)
+	d := &api.StreamSupplierDescription{Type: api.Random}
+	ctx := context.Background()
+
+	// the failures are returned in order
+	_, err := f.CreateRandomStream(ctx, d)
+	assert.Equal(t, com.ErrStreamNotImplemented, err)
+	_, err = f.CreateRandomStream(ctx, d)
+	assert.Equal(t, com.ErrIllegalConfiguration, err)
+	streamID, err := f.CreateRandomStream(ctx, d)
+	assert.Nil(t, err)
+	assert.Equal(t, []api.StreamSupplierDescription{*d}, f.Descriptions())
+
+	id, err := f.OpenStreamConnection(ctx, *streamID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, f.Connections())
+	mod, err := f.ReadStreamConnection(ctx, 10, *id)
+	assert.Nil(t, err)
+	assert.Equal(t, "ab😀", runes(t, mod))
+	assert.Nil(t, f.CloseStreamConnection(ctx, *id))
+	assert.Equal(t, com.ErrStreamNotFound, f.CloseStreamConnection(ctx, *id))
+	assert.Equal(t, 0, f.Connections())
+}
+
+func TestFakeStreams(t *testing.T) {
+	f := NewFake(byType)
+	f.Closed = true
+	ctx := context.Background()
+	random, err := f.CreateRandomStream(ctx, &api.StreamSupplierDescription{Type: api.Random})
+	assert.Nil(t, err)
+	dictionary, err := f.CreateRandomStream(ctx, &api.StreamSupplierDescription{Type: api.Dictionary})
+	assert.Nil(t, err)
+	shared := f.AddStream("shared")
+
+	// each Stream serves its own text
+	for streamID, text := range map[int64]string{*random: "Random", *dictionary: "Dictionary", shared: "shared"} {
+		mod, err := f.ReadStream(ctx, 10, streamID, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, text, runes(t, mod))
+	}
+
+	// the Streams were deleted together with their last connection
+	for _, streamID := range []int64{*random, *dictionary, shared} {
+		_, err = f.OpenStreamConnection(ctx, streamID)
+		assert.Equal(t, com.ErrStreamNotFound, err)
+	}
+}
+
+func TestFakeReadStreamReconnect(t *testing.T) {
+	f := NewFake(Text("abcdef"))
+	f.Closed = true
+	f.DropAfter = 2
+	streamID, err := f.CreateRandomStream(context.Background(), &api.StreamSupplierDescription{Type: api.Random})
+	assert.Nil(t, err)
+	mod, err := f.ReadStream(context.Background(), 10, *streamID, nil)
+	assert.Nil(t, err)
+	// the Stream survives the lost connection
+	assert.Equal(t, "abcdef", runes(t, mod))
+	assert.Equal(t, 0, f.Connections())
+}
+
+func TestFakeLatency(t *testing.T) {
+	f := NewFake(Text("abc"))
+	f.Latency = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := f.Version(ctx)
+	assert.Equal(t, com.ErrCanceled, err)
+}
@@ -0,0 +1,213 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// PathLobby is the path of the backend's multiplayer-lobby websocket
+const PathLobby = "/lobby/websocket"
+
+// errors
+var (
+	ErrLobbyRejected       = errors.New("the lobby rejected the request", errors.Warning, errors.Server)
+	ErrLobbyConnectionLost = errors.New("the connection to the lobby was lost", errors.Warning, errors.Server)
+)
+
+// MessageType identifies the kind of a lobby-Message
+type MessageType string
+
+// message types sent by the client
+const (
+	// CreateRoom creates a new Room hosted by Player using Description
+	CreateRoom MessageType = "create"
+	// JoinRoom adds Player to the Room identified by RoomID
+	JoinRoom MessageType = "join"
+	// StartRace starts the race in the host's Room
+	StartRace MessageType = "start"
+	// ReportProgress tells the other players about the client's Progress
+	ReportProgress MessageType = "progress"
+	// LeaveRoom removes the client from its Room
+	LeaveRoom MessageType = "leave"
+)
+
+// message types sent by the server
+const (
+	// RoomUpdated carries the Room's current state
+	RoomUpdated MessageType = "room"
+	// RaceStarted carries the StreamID of the model-stream shared by all
+	// players
+	RaceStarted MessageType = "started"
+	// StandingsUpdated carries the Players ordered by their Progress
+	StandingsUpdated MessageType = "standings"
+	// PlayerEliminated carries the Player eliminated in Round
+	PlayerEliminated MessageType = "eliminated"
+	// RaceFinished carries the winning Player
+	RaceFinished MessageType = "finished"
+	// LobbyFailure carries the Error, that made the server reject a request
+	LobbyFailure MessageType = "error"
+)
+
+// Message is exchanged with the lobby. Only the fields needed by the
+// Message's Type are set
+type Message struct {
+	Type        MessageType                    `json:"type"`
+	Player      string                         `json:"player,omitempty"`
+	RoomID      string                         `json:"roomID,omitempty"`
+	Description *api.StreamSupplierDescription `json:"description,omitempty"`
+	Progress    *Progress                      `json:"progress,omitempty"`
+	Room        *Room                          `json:"room,omitempty"`
+	Players     []Player                       `json:"players,omitempty"`
+	StreamID    int64                          `json:"streamID,omitempty"`
+	Round       int                            `json:"round,omitempty"`
+	Error       string                         `json:"error,omitempty"`
+}
+
+// Err returns the error carried by a LobbyFailure-Message. For other Messages
+// nil is returned
+func (m Message) Err() errors.Error {
+	if m.Type != LobbyFailure {
+		return nil
+	}
+	return ErrLobbyRejected.Append(m.Error)
+}
+
+// Room is a group of players racing each other
+type Room struct {
+	ID      string   `json:"id"`
+	Host    string   `json:"host"`
+	Players []Player `json:"players"`
+	Running bool     `json:"running"`
+}
+
+// Player is a participant of a Room
+type Player struct {
+	Name       string   `json:"name"`
+	Progress   Progress `json:"progress"`
+	Eliminated bool     `json:"eliminated,omitempty"`
+}
+
+// Progress summarizes how far a player got in the current race
+type Progress struct {
+	Characters        int     `json:"characters"`
+	CorrectCharacters int     `json:"correctCharacters"`
+	Misses            int     `json:"misses"`
+	NetWPM            float64 `json:"netWPM"`
+}
+
+// ProgressOf derives the Progress from the given Statistics, which were
+// gathered during the given duration
+func ProgressOf(s comparison.Statistics, elapsed time.Duration) Progress {
+	return Progress{
+		Characters:        s.TotalCharacters(),
+		CorrectCharacters: s.CorrectCharacters(),
+		Misses:            s.TotalMisses(),
+		NetWPM:            comparison.RatesFor(s, elapsed).NetWPM(),
+	}
+}
+
+// Lobby is a connection to the backend's multiplayer-lobby. All methods are
+// safe for concurrent use
+type Lobby struct {
+	conn     net.Conn
+	messages chan Message
+	done     chan bool
+	once     sync.Once
+
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// ConnectLobby opens a websocket-connection to the backend's lobby
+func (c *Client) ConnectLobby(ctx context.Context) (*Lobby, errors.Error) {
+	conn, err := dial(ctx, c.websocketURL(PathLobby))
+	if err != nil {
+		return nil, failed(ctx, err)
+	}
+	return NewLobby(conn), nil
+}
+
+// NewLobby speaks the lobby-protocol over the given connection, where each
+// Message is encoded as JSON
+func NewLobby(c net.Conn) *Lobby {
+	l := &Lobby{
+		conn:     c,
+		messages: make(chan Message),
+		done:     make(chan bool),
+		encoder:  json.NewEncoder(c),
+	}
+	go l.read()
+	return l
+}
+
+func (l *Lobby) read() {
+	defer close(l.messages)
+	decoder := json.NewDecoder(l.conn)
+	for {
+		var m Message
+		if err := decoder.Decode(&m); err != nil {
+			return
+		}
+		select {
+		case l.messages <- m:
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// Messages returns the stream of Messages sent by the server. It is closed,
+// when the connection is lost or closed
+func (l *Lobby) Messages() <-chan Message {
+	return l.messages
+}
+
+// Create creates a new Room hosted by the given player. The model-stream is
+// created according to the given description
+func (l *Lobby) Create(player string, description *api.StreamSupplierDescription) errors.Error {
+	return l.send(Message{Type: CreateRoom, Player: player, Description: description})
+}
+
+// Join adds the given player to the Room with the given id
+func (l *Lobby) Join(roomID, player string) errors.Error {
+	return l.send(Message{Type: JoinRoom, RoomID: roomID, Player: player})
+}
+
+// Start starts the race. Only the Room's host may start it
+func (l *Lobby) Start() errors.Error {
+	return l.send(Message{Type: StartRace})
+}
+
+// Report sends the client's Progress to the other players
+func (l *Lobby) Report(p Progress) errors.Error {
+	return l.send(Message{Type: ReportProgress, Progress: &p})
+}
+
+// Leave removes the client from its Room
+func (l *Lobby) Leave() errors.Error {
+	return l.send(Message{Type: LeaveRoom})
+}
+
+// Close closes the connection. Closing a closed Lobby has no effect
+func (l *Lobby) Close() {
+	l.once.Do(func() {
+		close(l.done)
+		l.conn.Close()
+	})
+}
+
+func (l *Lobby) send(m Message) errors.Error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if err := l.encoder.Encode(m); err != nil {
+		return ErrLobbyConnectionLost.Append(err.Error())
+	}
+	return nil
+}
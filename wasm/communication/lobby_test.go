@@ -0,0 +1,229 @@
+package communication
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+)
+
+// fakeLobby is an in-process lobby-server. Rounds are triggered by the tests
+type fakeLobby struct {
+	mutex sync.Mutex
+	rooms map[string]*fakeRoom
+}
+
+type fakeRoom struct {
+	room    Room
+	round   int
+	clients map[string]chan<- Message
+}
+
+func newFakeLobby() *fakeLobby {
+	return &fakeLobby{
+		rooms: make(map[string]*fakeRoom),
+	}
+}
+
+// connect returns a Lobby connected to the fake server
+func (f *fakeLobby) connect() *Lobby {
+	server, client := net.Pipe()
+	go f.serve(server)
+	return NewLobby(client)
+}
+
+func (f *fakeLobby) serve(c net.Conn) {
+	// the outbox decouples broadcasts from clients, that don't read
+	out := make(chan Message, 100)
+	go func() {
+		e := json.NewEncoder(c)
+		for m := range out {
+			e.Encode(m)
+		}
+	}()
+	defer close(out)
+	defer c.Close()
+
+	var room *fakeRoom
+	var player string
+	d := json.NewDecoder(c)
+	for {
+		var m Message
+		if d.Decode(&m) != nil {
+			return
+		}
+		f.mutex.Lock()
+		switch m.Type {
+		case CreateRoom:
+			room = &fakeRoom{
+				room:    Room{ID: "room" + strconv.Itoa(len(f.rooms)+1), Host: m.Player},
+				clients: make(map[string]chan<- Message),
+			}
+			f.rooms[room.room.ID] = room
+			player = m.Player
+			room.add(player, out)
+		case JoinRoom:
+			r, ok := f.rooms[m.RoomID]
+			if !ok {
+				out <- Message{Type: LobbyFailure, Error: "unknown room"}
+				break
+			}
+			room, player = r, m.Player
+			room.add(player, out)
+		case StartRace:
+			if room == nil || room.room.Host != player {
+				out <- Message{Type: LobbyFailure, Error: "only the host may start the race"}
+				break
+			}
+			room.room.Running = true
+			room.broadcast(Message{Type: RaceStarted, StreamID: 42})
+		case ReportProgress:
+			for i := range room.room.Players {
+				if room.room.Players[i].Name == player {
+					room.room.Players[i].Progress = *m.Progress
+				}
+			}
+			room.broadcast(Message{Type: StandingsUpdated, Players: room.standings()})
+		}
+		f.mutex.Unlock()
+	}
+}
+
+// round eliminates the slowest player of the given room. The race finishes,
+// when a single player is left
+func (f *fakeLobby) round(id string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	r := f.rooms[id]
+	r.round++
+	standings := r.standings()
+	var left []Player
+	for _, p := range standings {
+		if !p.Eliminated {
+			left = append(left, p)
+		}
+	}
+	slowest := left[len(left)-1].Name
+	for i := range r.room.Players {
+		if r.room.Players[i].Name == slowest {
+			r.room.Players[i].Eliminated = true
+		}
+	}
+	r.broadcast(Message{Type: PlayerEliminated, Player: slowest, Round: r.round})
+	if len(left) == 2 {
+		r.room.Running = false
+		r.broadcast(Message{Type: RaceFinished, Player: left[0].Name})
+	}
+}
+
+func (r *fakeRoom) add(player string, out chan<- Message) {
+	r.clients[player] = out
+	r.room.Players = append(r.room.Players, Player{Name: player})
+	room := r.room
+	r.broadcast(Message{Type: RoomUpdated, Room: &room})
+}
+
+func (r *fakeRoom) broadcast(m Message) {
+	for _, out := range r.clients {
+		out <- m
+	}
+}
+
+func (r *fakeRoom) standings() []Player {
+	players := append([]Player(nil), r.room.Players...)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Progress.CorrectCharacters > players[j].Progress.CorrectCharacters
+	})
+	return players
+}
+
+// next returns the next Message received by l
+func next(t *testing.T, l *Lobby) Message {
+	select {
+	case m, ok := <-l.Messages():
+		assert.True(t, ok, "the connection was closed")
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return Message{}
+	}
+}
+
+func TestLobby(t *testing.T) {
+	server := newFakeLobby()
+	alice := server.connect()
+	defer alice.Close()
+	bob := server.connect()
+	defer bob.Close()
+
+	assert.Nil(t, alice.Create("alice", &api.StreamSupplierDescription{Type: api.Random}))
+	m := next(t, alice)
+	assert.Equal(t, RoomUpdated, m.Type)
+	assert.Equal(t, "alice", m.Room.Host)
+	id := m.Room.ID
+
+	assert.Nil(t, bob.Join(id, "bob"))
+	for _, l := range []*Lobby{alice, bob} {
+		m = next(t, l)
+		assert.Equal(t, RoomUpdated, m.Type)
+		assert.Equal(t, []Player{{Name: "alice"}, {Name: "bob"}}, m.Room.Players)
+	}
+
+	assert.Nil(t, bob.Start())
+	m = next(t, bob)
+	assert.Equal(t, ErrLobbyRejected.Type(), m.Err().Type())
+
+	assert.Nil(t, alice.Start())
+	for _, l := range []*Lobby{alice, bob} {
+		m = next(t, l)
+		assert.Nil(t, m.Err())
+		assert.Equal(t, RaceStarted, m.Type)
+		assert.Equal(t, int64(42), m.StreamID)
+	}
+
+	assert.Nil(t, bob.Report(Progress{Characters: 6, CorrectCharacters: 5}))
+	next(t, alice)
+	next(t, bob)
+	assert.Nil(t, alice.Report(Progress{Characters: 10, CorrectCharacters: 10, NetWPM: 60}))
+	for _, l := range []*Lobby{alice, bob} {
+		m = next(t, l)
+		assert.Equal(t, StandingsUpdated, m.Type)
+		assert.Equal(t, "alice", m.Players[0].Name)
+		assert.Equal(t, 60.0, m.Players[0].Progress.NetWPM)
+		assert.Equal(t, "bob", m.Players[1].Name)
+	}
+
+	server.round(id)
+	for _, l := range []*Lobby{alice, bob} {
+		m = next(t, l)
+		assert.Equal(t, Message{Type: PlayerEliminated, Player: "bob", Round: 1}, m)
+		m = next(t, l)
+		assert.Equal(t, Message{Type: RaceFinished, Player: "alice"}, m)
+	}
+}
+
+func TestLobbyUnknownRoom(t *testing.T) {
+	l := newFakeLobby().connect()
+	defer l.Close()
+	assert.Nil(t, l.Join("unknown", "bob"))
+	m := next(t, l)
+	assert.Equal(t, LobbyFailure, m.Type)
+	assert.Equal(t, "the lobby rejected the request (unknown room)", m.Err().Error())
+}
+
+func TestLobbyClose(t *testing.T) {
+	server, client := net.Pipe()
+	l := NewLobby(client)
+	server.Close()
+	_, ok := <-l.Messages()
+	assert.False(t, ok)
+	assert.Equal(t, ErrLobbyConnectionLost.Type(), l.Leave().Type())
+	l.Close()
+	l.Close()
+}
@@ -0,0 +1,274 @@
+package communication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+	"unicode/utf8"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Backoff configures the delays between the attempts to reconnect a stream.
+// The first attempt is made after Initial, each following one after twice the
+// previous delay, but at most after Max. The stream is given up after
+// Attempts attempts in a row, that didn't receive any new Characters
+type Backoff struct {
+	Initial  time.Duration
+	Max      time.Duration
+	Attempts int
+}
+
+// DefaultBackoff gives up after about half a minute
+var DefaultBackoff = Backoff{
+	Initial:  250 * time.Millisecond,
+	Max:      8 * time.Second,
+	Attempts: 8,
+}
+
+// delay returns how long to wait before the attempt following the given
+// number of failed attempts
+func (b Backoff) delay(failures int) time.Duration {
+	d := b.Initial
+	for i := 0; i < failures && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// ReadStream returns a channel of Characters with the given buffer and spawns
+// a new goroutine, which opens a connection to the Stream with the given id
+// and pipes its content into the returned channel. Each connection starts at
+// the Stream's beginning, so, if the connection is lost, a new one is opened
+// according to the Client's Backoff, and the Characters received before are
+// skipped. While reconnecting, warn is called with ErrReconnecting, and with
+// nil, once a new connection is open. The lost connection is closed
+// afterwards, so the Stream isn't deleted meanwhile. If the stream can't be
+// resumed, warn is called with the last error. The returned channel is closed,
+// when the stream can't be resumed, the server sends an illegal value, or ctx
+// is done. warn may be nil
+func (c *Client) ReadStream(ctx context.Context, buffer uint, streamID int64, warn func(errors.Error)) (<-chan comparison.Character, errors.Error) {
+	if warn == nil {
+		warn = func(errors.Error) {}
+	}
+	id, conn, err := c.connectStream(ctx, streamID)
+	if err != nil {
+		return nil, err
+	}
+	mod := make(chan comparison.Character, int(buffer))
+	go func() {
+		defer close(mod)
+		offset, failures := 0, 0
+		for {
+			n, err := readStream(ctx, conn, buffer, offset, mod)
+			offset += n
+			if n > 0 {
+				failures = 0
+			}
+			if err == nil || err.Type() != ErrStreamConnectionLost.Type() {
+				// a failure to close the connection doesn't matter to the user
+				go c.CloseStreamConnection(context.Background(), id)
+				return
+			}
+			// the backend deletes a Stream together with its last connection,
+			// so the lost connection is closed after the new one was opened
+			lost := id
+			id, conn, err = c.reconnect(ctx, streamID, &failures, warn)
+			go c.CloseStreamConnection(context.Background(), lost)
+			if err != nil {
+				if ctx.Err() == nil {
+					warn(err)
+				}
+				return
+			}
+			warn(nil)
+		}
+	}()
+	return mod, nil
+}
+
+// reconnect opens a new connection to the Stream with the given id. It waits
+// according to the Client's Backoff before each attempt and counts the failed
+// ones. Only connection-failures are retried
+func (c *Client) reconnect(ctx context.Context, streamID int64, failures *int, warn func(errors.Error)) (int64, net.Conn, errors.Error) {
+	err := ErrStreamConnectionLost
+	for *failures < c.Backoff.Attempts {
+		warn(ErrReconnecting)
+		select {
+		case <-time.After(c.Backoff.delay(*failures)):
+		case <-ctx.Done():
+			return 0, nil, ErrCanceled
+		}
+		*failures++
+		var id int64
+		var conn net.Conn
+		id, conn, err = c.connectStream(ctx, streamID)
+		if err == nil {
+			return id, conn, nil
+		}
+		if err.Type() != ErrServerConnectionFailed.Type() {
+			return 0, nil, err
+		}
+	}
+	return 0, nil, err
+}
+
+// connectStream opens a new connection to the Stream with the given id and
+// returns the connection's id as well as its websocket
+func (c *Client) connectStream(ctx context.Context, streamID int64) (int64, net.Conn, errors.Error) {
+	id, err := c.OpenStreamConnection(ctx, streamID)
+	if err != nil {
+		return 0, nil, err
+	}
+	conn, err := c.dialStreamConnection(ctx, *id)
+	if err != nil {
+		go c.CloseStreamConnection(context.Background(), *id)
+		return 0, nil, err
+	}
+	return *id, conn, nil
+}
+
+// readStream speaks the stream-protocol over the given connection: the client
+// requests a number of Characters, which the server answers with a sequence of
+// JSON-values. Each value is either a single Character encoded as its
+// code point or as a string, or an array of those. Values may be batched into
+// one message or split across several reads. As a number is only complete,
+// when it is followed by a delimiter, the connections opened by dial end each
+// message with a newline. The first skip Characters are dropped, the others
+// are piped into mod. readStream blocks, until the connection is closed by the
+// server, a value can't be decoded, or ctx is done, and closes the connection.
+// It returns the number of Characters piped into mod and why the stream ended:
+// ErrStreamConnectionLost, ErrUnexpectedResponseFormat, or nil, if ctx is done
+func readStream(ctx context.Context, conn net.Conn, buffer uint, skip int, mod chan<- comparison.Character) (int, errors.Error) {
+	reqLimit := buffer / 10
+	if reqLimit == 0 {
+		reqLimit = 1
+	}
+	finished := make(chan bool)
+	defer close(finished)
+	// closing the connection interrupts a pending read
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-finished:
+		}
+		conn.Close()
+	}()
+	lost := func(err error) errors.Error {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return ErrStreamConnectionLost.Append(err.Error())
+	}
+
+	request := func(n uint) error {
+		b, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		_, err = conn.Write(b)
+		return err
+	}
+	if err := request(reqLimit); err != nil {
+		return 0, lost(err)
+	}
+	piped := 0
+	received := uint(0)
+	decoder := json.NewDecoder(conn)
+	for {
+		var frame json.RawMessage
+		if err := decoder.Decode(&frame); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				return piped, ErrUnexpectedResponseFormat.Append(err.Error())
+			}
+			return piped, lost(err)
+		}
+		chars, e := decodeCharacters(frame)
+		if e != nil {
+			return piped, e
+		}
+		for _, c := range chars {
+			if skip > 0 {
+				skip--
+			} else {
+				select {
+				case mod <- c:
+					piped++
+				case <-ctx.Done():
+					return piped, nil
+				}
+			}
+			received++
+			if received >= reqLimit {
+				if err := request(received); err != nil {
+					return piped, lost(err)
+				}
+				received = 0
+			}
+		}
+	}
+}
+
+// decodeCharacters decodes a single JSON-value sent by the server
+func decodeCharacters(frame json.RawMessage) ([]comparison.Character, errors.Error) {
+	frame = bytes.TrimSpace(frame)
+	if len(frame) == 0 {
+		return nil, ErrUnexpectedResponseFormat
+	}
+	if frame[0] != '[' {
+		return decodeCharacter(frame)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(frame, &items); err != nil {
+		return nil, ErrUnexpectedResponseFormat.Append(err.Error())
+	}
+	var chars []comparison.Character
+	for _, item := range items {
+		item = bytes.TrimSpace(item)
+		if len(item) == 0 || item[0] == '[' {
+			return nil, ErrUnexpectedResponseFormat.Append(string(frame))
+		}
+		c, err := decodeCharacter(item)
+		if err != nil {
+			return nil, err
+		}
+		chars = append(chars, c...)
+	}
+	return chars, nil
+}
+
+// decodeCharacter decodes a code point or a string, whose runes are returned
+// in order. Strings may contain runes outside the Basic Multilingual Plane,
+// either as UTF-8 or as escaped surrogate pairs
+func decodeCharacter(item json.RawMessage) ([]comparison.Character, errors.Error) {
+	if item[0] == '"' {
+		var s string
+		if err := json.Unmarshal(item, &s); err != nil {
+			return nil, ErrUnexpectedResponseFormat.Append(err.Error())
+		}
+		var chars []comparison.Character
+		for _, r := range s {
+			chars = append(chars, api.BasicCharacter(r))
+		}
+		return chars, nil
+	}
+	// literals like null would be decoded as the zero value
+	if item[0] != '-' && (item[0] < '0' || item[0] > '9') {
+		return nil, ErrUnexpectedResponseFormat.Append(string(item))
+	}
+	var c api.BasicCharacter
+	if err := json.Unmarshal(item, &c); err != nil {
+		return nil, ErrUnexpectedResponseFormat.Append(err.Error())
+	}
+	if !utf8.ValidRune(c.Rune()) {
+		return nil, ErrUnexpectedResponseFormat.Append(string(item))
+	}
+	return []comparison.Character{c}, nil
+}
@@ -0,0 +1,177 @@
+package communication
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+// chunkedConn is a fake net.Conn, whose reads return the given chunks in order.
+// After the last chunk, reads return io.EOF, unless the connection is kept
+// open, in which case they block until it is closed. Writes are recorded
+type chunkedConn struct {
+	chunks [][]byte
+	open   bool
+	closed chan bool
+	once   sync.Once
+
+	mutex   sync.Mutex
+	written []string
+}
+
+func newChunkedConn(open bool, chunks ...string) *chunkedConn {
+	c := &chunkedConn{
+		open:   open,
+		closed: make(chan bool),
+	}
+	for _, chunk := range chunks {
+		c.chunks = append(c.chunks, []byte(chunk))
+	}
+	return c
+}
+
+func (c *chunkedConn) Read(b []byte) (int, error) {
+	if len(c.chunks) == 0 {
+		if c.open {
+			<-c.closed
+		}
+		return 0, io.EOF
+	}
+	n := copy(b, c.chunks[0])
+	c.chunks[0] = c.chunks[0][n:]
+	if len(c.chunks[0]) == 0 {
+		c.chunks = c.chunks[1:]
+	}
+	return n, nil
+}
+
+func (c *chunkedConn) Write(b []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.written = append(c.written, string(b))
+	return len(b), nil
+}
+
+func (c *chunkedConn) requests() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.written
+}
+
+func (c *chunkedConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *chunkedConn) LocalAddr() net.Addr                { return nil }
+func (c *chunkedConn) RemoteAddr() net.Addr               { return nil }
+func (c *chunkedConn) SetDeadline(t time.Time) error      { return nil }
+func (c *chunkedConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *chunkedConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// split cuts s into chunks of the given size
+func split(s string, size int) []string {
+	var chunks []string
+	for len(s) > size {
+		chunks = append(chunks, s[:size])
+		s = s[size:]
+	}
+	return append(chunks, s)
+}
+
+// pipe runs readStream in the background and returns the Characters read
+func pipe(ctx context.Context, c net.Conn, buffer uint) <-chan comparison.Character {
+	mod := make(chan comparison.Character, int(buffer))
+	go func() {
+		defer close(mod)
+		readStream(ctx, c, buffer, 0, mod)
+	}()
+	return mod
+}
+
+// runes reads the given stream until it is closed
+func runes(t *testing.T, mod <-chan comparison.Character) string {
+	var r []rune
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case c, ok := <-mod:
+			if !ok {
+				return string(r)
+			}
+			r = append(r, c.Rune())
+		case <-timeout:
+			t.Fatal("the stream wasn't closed")
+		}
+	}
+}
+
+// frames contains single, batched and non-BMP Characters in all encodings
+const frames = `97 "b"[99, 100]
+"😀" "😀x" 128512 ["y", 122]`
+
+func TestReadStreamChunks(t *testing.T) {
+	for size := 1; size <= len(frames); size++ {
+		c := newChunkedConn(false, split(frames, size)...)
+		assert.Equal(t, "abcd😀😀x😀yz", runes(t, pipe(context.Background(), c, 50)), "chunk size %d", size)
+		// ten Characters are requested in batches of five
+		assert.Equal(t, []string{"5", "5", "5"}, c.requests(), "chunk size %d", size)
+	}
+}
+
+func TestReadStreamBoundaries(t *testing.T) {
+	for i := 1; i < len(frames); i++ {
+		c := newChunkedConn(false, frames[:i], frames[i:])
+		assert.Equal(t, "abcd😀😀x😀yz", runes(t, pipe(context.Background(), c, 50)), "split at %d", i)
+	}
+}
+
+func TestReadStreamIllegalFrames(t *testing.T) {
+	for _, payload := range []string{
+		`97 {"a": 98} 99`,
+		`97 [[98]] 99`,
+		`97 [98, {}] 99`,
+		`97 null 99`,
+		`97 true 99`,
+		`97 -1 99`,
+		`97 x 99`,
+	} {
+		c := newChunkedConn(true, split(payload, 2)...)
+		mod := make(chan comparison.Character, 10)
+		n, err := readStream(context.Background(), c, 10, 0, mod)
+		assert.Equal(t, 1, n, payload)
+		assert.Equal(t, ErrUnexpectedResponseFormat.Type(), err.Type(), payload)
+		<-c.closed
+	}
+}
+
+func TestReadStreamSkip(t *testing.T) {
+	c := newChunkedConn(false, `97 "bcd" 101`+"\n")
+	mod := make(chan comparison.Character, 10)
+	n, err := readStream(context.Background(), c, 10, 2, mod)
+	close(mod)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "cde", runes(t, mod))
+	// the skipped Characters were requested as well
+	assert.Equal(t, []string{"1", "1", "1", "1", "1", "1"}, c.requests())
+	assert.Equal(t, ErrStreamConnectionLost.Type(), err.Type())
+}
+
+func TestReadStreamCancel(t *testing.T) {
+	c := newChunkedConn(true, "97\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	mod := pipe(ctx, c, 10)
+	assert.Equal(t, 'a', (<-mod).Rune())
+	cancel()
+	_, ok := <-mod
+	assert.False(t, ok)
+	<-c.closed
+}
@@ -0,0 +1,190 @@
+//go:build js && wasm
+// +build js,wasm
+
+package communication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gopherjs/gopherwasm/js"
+	"github.com/gopherjs/websocket/websocketjs"
+)
+
+// errNoDeadlines is returned by the deadline-setters of the browser's
+// websocket-connections
+var errNoDeadlines = fmt.Errorf("the browser's WebSocket api doesn't support deadlines")
+
+// dial opens a websocket-connection using the browser's WebSocket api. The
+// received messages are delimited by a newline. If ctx is done before the
+// connection is established, the attempt is aborted
+func dial(ctx context.Context, url string) (net.Conn, error) {
+	ws, err := websocketjs.New(url)
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(ws)
+	select {
+	case err := <-c.opened:
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		return c, nil
+	case <-ctx.Done():
+		c.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// conn adapts a browser-WebSocket to the net.Conn interface. Each Write is
+// sent as a single text-message. The received messages are queued, each
+// followed by a newline, as soon as they arrive. Read returns the queued bytes
+// in order, so a message may span several Reads and a Read may span several
+// messages
+type conn struct {
+	ws *websocketjs.WebSocket
+	// opened receives nil, when the connection is established, or an error,
+	// if it is closed before
+	opened chan error
+	// received is signaled, whenever pending or closed changed
+	received  chan bool
+	listeners map[string]js.Func
+
+	mutex   sync.Mutex
+	pending []byte
+	closed  bool
+}
+
+func newConn(ws *websocketjs.WebSocket) *conn {
+	c := &conn{
+		ws:       ws,
+		opened:   make(chan error, 1),
+		received: make(chan bool, 1),
+	}
+	// the messages arrive as strings or ArrayBuffers
+	ws.Set("binaryType", "arraybuffer")
+	// the listeners run synchronously, so the messages are queued in order
+	c.listeners = map[string]js.Func{
+		"open": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			c.open(nil)
+			return nil
+		}),
+		"message": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			c.receive(messageData(args[0].Get("data")))
+			return nil
+		}),
+		"close": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			c.open(fmt.Errorf("the websocket-connection was closed with code %d", args[0].Get("code").Int()))
+			c.close()
+			return nil
+		}),
+	}
+	for event, listener := range c.listeners {
+		ws.Call("addEventListener", event, listener)
+	}
+	return c
+}
+
+// messageData converts the data of a message-event to a byte slice
+func messageData(data js.Value) []byte {
+	if data.Type() == js.TypeString {
+		return []byte(data.String())
+	}
+	bytes := js.Global().Get("Uint8Array").New(data)
+	b := make([]byte, bytes.Length())
+	for i := range b {
+		b[i] = byte(bytes.Index(i).Int())
+	}
+	return b
+}
+
+// open reports the outcome of the connection-attempt. Only the first outcome
+// is reported. The listeners must not block, so neither does open
+func (c *conn) open(err error) {
+	select {
+	case c.opened <- err:
+	default:
+	}
+}
+
+// receive queues the given message
+func (c *conn) receive(message []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return
+	}
+	c.pending = append(c.pending, message...)
+	c.pending = append(c.pending, '\n')
+	c.signal()
+}
+
+// close marks the connection as closed and removes the listeners. The queued
+// bytes can still be read. Closing a closed connection has no effect
+func (c *conn) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for event, listener := range c.listeners {
+		c.ws.Call("removeEventListener", event, listener)
+		listener.Release()
+	}
+	c.signal()
+}
+
+// signal wakes up a blocked Read. The caller must hold the mutex
+func (c *conn) signal() {
+	select {
+	case c.received <- true:
+	default:
+	}
+}
+
+// Read blocks until bytes were received or the connection is closed. After all
+// queued bytes were read, a closed connection returns io.EOF
+func (c *conn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	for {
+		c.mutex.Lock()
+		if len(c.pending) > 0 {
+			n := copy(b, c.pending)
+			c.pending = c.pending[n:]
+			c.mutex.Unlock()
+			return n, nil
+		}
+		closed := c.closed
+		c.mutex.Unlock()
+		if closed {
+			return 0, io.EOF
+		}
+		<-c.received
+	}
+}
+
+func (c *conn) Write(b []byte) (int, error) {
+	if err := c.ws.Send(js.ValueOf(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *conn) Close() error {
+	c.close()
+	return c.ws.Close()
+}
+
+func (c *conn) LocalAddr() net.Addr                { return nil }
+func (c *conn) RemoteAddr() net.Addr               { return nil }
+func (c *conn) SetDeadline(t time.Time) error      { return errNoDeadlines }
+func (c *conn) SetReadDeadline(t time.Time) error  { return errNoDeadlines }
+func (c *conn) SetWriteDeadline(t time.Time) error { return errNoDeadlines }
@@ -0,0 +1,78 @@
+//go:build !js
+// +build !js
+
+package communication
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dial opens a websocket-connection for native (non-browser) builds. The
+// received messages are delimited by a newline
+func dial(ctx context.Context, url string) (net.Conn, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: ws}, nil
+}
+
+// conn adapts a websocket.Conn to the net.Conn interface. Each Write is sent
+// as a single text-message. Read returns the content of the received messages
+// in order, each followed by a newline
+type conn struct {
+	*websocket.Conn
+	reader io.Reader
+	// end is set, when the current message was read, but not its delimiter
+	end bool
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	for {
+		if c.end {
+			c.end = false
+			c.reader = nil
+			return copy(b, "\n"), nil
+		}
+		if c.reader == nil {
+			_, r, err := c.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			c.reader = r
+		}
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			c.end = true
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *conn) Write(b []byte) (int, error) {
+	err := c.WriteMessage(websocket.TextMessage, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *conn) SetDeadline(t time.Time) error {
+	err := c.SetReadDeadline(t)
+	if err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
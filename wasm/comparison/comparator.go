@@ -6,6 +6,9 @@
 package comparison
 
 import (
+	"math"
+	"sort"
+	"sync"
 	"time"
 	"unicode"
 
@@ -20,9 +23,20 @@ const BS api.BasicCharacter = '\u0008'
 // non-printable Character as defined by the unicode.IsPrint method
 var ErrIllegalModelInput = errors.New("the model-input-stream contained an illegal character", errors.Critical, errors.Input)
 
+// RollingWindow is the duration of the sliding window, which is used for
+// calculating Statistics.RollingCPM
+var RollingWindow = 10 * time.Second
+
 // Character is the required type for the input-streams
 type Character api.Character
 
+// Timed is implemented by Characters, which know the time they were typed at.
+// Compare uses this time instead of the time of receipt, if an attempt-input
+// implements Timed
+type Timed interface {
+	Time() time.Time
+}
+
 // Comparison is the comparison's output-stream type. It holds the stream's current
 // state, the latest changes and some statistics
 type Comparison interface {
@@ -49,10 +63,18 @@ type Modification interface {
 	// Correct returns true, if the considered Character equals its model. This
 	// value is always true, if the Modification is a deletion
 	Correct() bool
+	// Time returns the time, when the Character was received
+	Time() time.Time
+	// Latency returns the time passed since the previous Modification. It is
+	// zero for the first Modification
+	Latency() time.Duration
+	// Expected returns the model-Character at the Modification's Position. It
+	// is nil, if the Modification is a deletion
+	Expected() Character
 }
 
 // Statistics contains information on the total amount of Characters, words and
-// misses, as well as the failure-quote
+// misses, as well as the failure-quote and the time passed between keystrokes
 type Statistics interface {
 	// TotalCharacters returns the total amount of Characters minus the amount
 	// of backspaces
@@ -68,6 +90,26 @@ type Statistics interface {
 	TotalStrokes() int
 	// FailureRate returns the TotalMisses per TotalStrokes
 	FailureRate() float64
+	// MeanLatency returns the average time between two keystrokes
+	MeanLatency() time.Duration
+	// MedianLatency returns the median of all times between two keystrokes
+	MedianLatency() time.Duration
+	// P95Latency returns the 95th percentile of all times between two
+	// keystrokes
+	P95Latency() time.Duration
+	// LongestPause returns the longest time between two keystrokes
+	LongestPause() time.Duration
+	// RollingCPM returns the amount of correct Characters per minute typed
+	// during the last RollingWindow
+	RollingCPM() float64
+	// Start returns the time of the first keystroke
+	Start() time.Time
+	// Misses returns the amount of misses per model-rune
+	Misses() map[rune]int
+	// Progression returns the characters per minute typed correctly within
+	// each interval, starting with the first keystroke. The last interval ends
+	// with the latest keystroke and may therefore be incomplete
+	Progression(interval time.Duration) []float64
 }
 
 // Compare compares the model and attempt Character-streams and channels the
@@ -98,8 +140,7 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 		correct:       true,
 		statusChanged: false,
 	}
-	var stats Statistics
-	stats = &statistics{
+	stats := &statistics{
 		totalCharacters: 0,
 		correctWords:    0,
 		totalMisses:     0,
@@ -122,6 +163,15 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 			return
 		}
 
+		now := time.Now()
+		if t, ok := a.(Timed); ok {
+			now = t.Time()
+		}
+		var latency time.Duration
+		if !stats.timing.last.IsZero() {
+			latency = now.Sub(stats.timing.last)
+		}
+
 		c := comparison{}
 		if a.Rune() == BS.Rune() {
 			if index == 0 {
@@ -150,6 +200,8 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 					position:  index - 1,
 					deletion:  true,
 					correct:   true,
+					time:      now,
+					latency:   latency,
 				},
 			}
 
@@ -200,6 +252,9 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 							position:  index,
 							deletion:  false,
 							correct:   true,
+							time:      now,
+							latency:   latency,
+							expected:  m,
 						},
 					}
 					index++
@@ -224,6 +279,9 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 							position:  index,
 							deletion:  false,
 							correct:   true,
+							time:      now,
+							latency:   latency,
+							expected:  m,
 						},
 					}
 					index++
@@ -249,11 +307,19 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 						position:  index,
 						deletion:  false,
 						correct:   false,
+						time:      now,
+						latency:   latency,
+						expected:  m,
 					},
 				}
+				c.statistics.missed = append(stats.missed, m.Rune())
 				index++
 			}
 		}
+		if c.statistics.missed == nil {
+			c.statistics.missed = stats.missed
+		}
+		c.statistics.timing = stats.timing.add(now, c.statistics.correctCharacters-stats.correctCharacters)
 		if stop {
 			return
 		}
@@ -266,7 +332,7 @@ func Compare(model, attempt <-chan Character, comp chan<- Comparison, timeout ..
 type comparison struct {
 	state      State
 	changes    []Modification
-	statistics Statistics
+	statistics *statistics
 }
 
 type state struct {
@@ -279,6 +345,9 @@ type modification struct {
 	position int
 	deletion bool
 	correct  bool
+	time     time.Time
+	latency  time.Duration
+	expected Character
 }
 
 type statistics struct {
@@ -288,6 +357,28 @@ type statistics struct {
 	totalMisses       int
 	totalStrokes      int
 	failureRate       float64
+	timing            timing
+	// missed holds the model-runes of all misses. Like timing's slices, it is
+	// shared between all succeeding Statistics
+	missed []rune
+	// sorted caches timing's latencies in ascending order for the percentiles
+	sorted     []time.Duration
+	sortedOnce sync.Once
+}
+
+// timing holds the data required for calculating time-based statistics. The
+// slices are shared between all succeeding Statistics. This is safe, as they
+// are only ever appended to and each Statistics only sees its own part.
+// correctTimes holds the times of the correct Characters, that weren't
+// deleted, so it may shrink. Its capacity is cut down then, so the next
+// append doesn't overwrite a time, that preceding Statistics still see
+type timing struct {
+	latencies    []time.Duration
+	correctTimes []time.Time
+	first        time.Time
+	last         time.Time
+	totalLatency time.Duration
+	longestPause time.Duration
 }
 
 func (c *comparison) State() State {
@@ -322,6 +413,18 @@ func (g *modification) Correct() bool {
 	return g.correct
 }
 
+func (g *modification) Time() time.Time {
+	return g.time
+}
+
+func (g *modification) Latency() time.Duration {
+	return g.latency
+}
+
+func (g *modification) Expected() Character {
+	return g.expected
+}
+
 func (g *statistics) TotalCharacters() int {
 	return g.totalCharacters
 }
@@ -346,6 +449,117 @@ func (g *statistics) FailureRate() float64 {
 	return g.failureRate
 }
 
+func (g *statistics) MeanLatency() time.Duration {
+	if len(g.timing.latencies) == 0 {
+		return 0
+	}
+	return g.timing.totalLatency / time.Duration(len(g.timing.latencies))
+}
+
+func (g *statistics) MedianLatency() time.Duration {
+	return g.percentile(0.5)
+}
+
+func (g *statistics) P95Latency() time.Duration {
+	return g.percentile(0.95)
+}
+
+func (g *statistics) LongestPause() time.Duration {
+	return g.timing.longestPause
+}
+
+func (g *statistics) RollingCPM() float64 {
+	t := g.timing.correctTimes
+	if len(t) == 0 {
+		return 0
+	}
+	from := g.timing.last.Add(-RollingWindow)
+	i := sort.Search(len(t), func(i int) bool {
+		return t[i].After(from)
+	})
+	window := RollingWindow
+	if g.timing.first.After(from) {
+		// the first keystroke happened less than RollingWindow ago
+		window = g.timing.last.Sub(g.timing.first)
+	}
+	if window <= 0 {
+		return 0
+	}
+	return float64(len(t)-i) / window.Minutes()
+}
+
+func (g *statistics) Start() time.Time {
+	return g.timing.first
+}
+
+func (g *statistics) Misses() map[rune]int {
+	misses := make(map[rune]int)
+	for _, r := range g.missed {
+		misses[r]++
+	}
+	return misses
+}
+
+func (g *statistics) Progression(interval time.Duration) []float64 {
+	if g.timing.first.IsZero() || interval <= 0 {
+		return nil
+	}
+	n := int(g.timing.last.Sub(g.timing.first)/interval) + 1
+	progression := make([]float64, n)
+	for _, t := range g.timing.correctTimes {
+		progression[int(t.Sub(g.timing.first)/interval)]++
+	}
+	for i := range progression {
+		progression[i] /= interval.Minutes()
+	}
+	return progression
+}
+
+// add returns a copy of t, that includes a keystroke received at now, which
+// changed the amount of correct Characters by the given delta
+func (t timing) add(now time.Time, delta int) timing {
+	if t.first.IsZero() {
+		t.first = now
+	} else {
+		l := now.Sub(t.last)
+		t.latencies = append(t.latencies, l)
+		t.totalLatency += l
+		if l > t.longestPause {
+			t.longestPause = l
+		}
+	}
+	switch {
+	case delta > 0:
+		t.correctTimes = append(t.correctTimes, now)
+	case delta < 0:
+		n := len(t.correctTimes) - 1
+		t.correctTimes = t.correctTimes[:n:n]
+	}
+	t.last = now
+	return t
+}
+
+// percentile returns the p-th percentile of g's latencies using the
+// nearest-rank method. The latencies are sorted only once per Statistics
+func (g *statistics) percentile(p float64) time.Duration {
+	latencies := g.timing.latencies
+	if len(latencies) == 0 {
+		return 0
+	}
+	g.sortedOnce.Do(func() {
+		g.sorted = make([]time.Duration, len(latencies))
+		copy(g.sorted, latencies)
+		sort.Slice(g.sorted, func(i, j int) bool {
+			return g.sorted[i] < g.sorted[j]
+		})
+	})
+	rank := int(math.Ceil(p*float64(len(g.sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return g.sorted[rank]
+}
+
 type characterbuffer struct {
 	buffer []Character
 	src    <-chan Character
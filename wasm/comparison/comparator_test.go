@@ -176,6 +176,98 @@ func TestComparisonLogic(t *testing.T) {
 	})
 }
 
+func TestTimestamps(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	go Compare(stream('a', 'b', 'c', 'd'), streamt(start, []rune{'a', 'b', 'x', rune(BS), 'c'}, []int{0, 100, 300, 1300, 1400}), c)
+	comp := consume(c)
+	if len(comp) != 5 {
+		assert.FailNow(t, "comparison-stream too short ("+strconv.Itoa(len(comp))+")")
+	}
+	t.Run("Changes()[0].Time()", func(t *testing.T) {
+		compare(t, func(i int) interface{} {
+			if i < 0 || i >= len(comp) {
+				return nil
+			}
+			return int(comp[i].Changes()[0].Time().Sub(start) / time.Millisecond)
+		}, 0, 100, 300, 1300, 1400)
+	})
+	t.Run("Changes()[0].Latency()", func(t *testing.T) {
+		compare(t, func(i int) interface{} {
+			if i < 0 || i >= len(comp) {
+				return nil
+			}
+			return comp[i].Changes()[0].Latency()
+		}, time.Duration(0), 100*time.Millisecond, 200*time.Millisecond, time.Second, 100*time.Millisecond)
+	})
+	stats := comp[len(comp)-1].Statistics()
+	assert.Equal(t, 350*time.Millisecond, stats.MeanLatency())
+	assert.Equal(t, 100*time.Millisecond, stats.MedianLatency())
+	assert.Equal(t, time.Second, stats.P95Latency())
+	assert.Equal(t, time.Second, stats.LongestPause())
+	// 3 correct characters within the first 1.4 seconds
+	assert.InDelta(t, 3/(1.4/60), stats.RollingCPM(), 0.001)
+	assert.Equal(t, time.Duration(0), comp[0].Statistics().MeanLatency())
+	assert.Equal(t, float64(0), comp[0].Statistics().RollingCPM())
+}
+
+func TestRollingCPM(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	// 10 characters in the first second, 1 character after a 15 second pause
+	go Compare(stream('a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a'), streamt(start,
+		[]rune{'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a', 'a'},
+		[]int{0, 100, 200, 300, 400, 500, 600, 700, 800, 900, 16000}), c)
+	comp := consume(c)
+	assert.Equal(t, 11, len(comp))
+	assert.InDelta(t, 10/(0.9/60), comp[9].Statistics().RollingCPM(), 0.001)
+	assert.InDelta(t, 1/(RollingWindow.Minutes()), comp[10].Statistics().RollingCPM(), 0.001)
+	assert.Equal(t, 15100*time.Millisecond, comp[10].Statistics().LongestPause())
+}
+
+func TestMisses(t *testing.T) {
+	c := make(chan Comparison)
+	go Compare(stream('a', 'b', 'a', 'c'), stream('x', rune(BS), 'a', 'x', 'x', rune(BS), rune(BS), 'b', 'a', 'c'), c)
+	comp := consume(c)
+	assert.Equal(t, 10, len(comp))
+	assert.Equal(t, map[rune]int{'a': 2, 'b': 1}, comp[len(comp)-1].Statistics().Misses())
+	assert.Equal(t, map[rune]int{'a': 1}, comp[0].Statistics().Misses())
+	assert.Equal(t, 'a', comp[0].Changes()[0].Expected().Rune())
+	assert.Nil(t, comp[1].Changes()[0].Expected())
+	assert.Equal(t, 'b', comp[3].Changes()[0].Expected().Rune())
+}
+
+func TestProgression(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	go Compare(stream('a', 'a', 'a', 'a', 'a'), streamt(start,
+		[]rune{'a', 'a', 'x', rune(BS), 'a', 'a'},
+		[]int{0, 500, 1000, 1200, 2500, 2900}), c)
+	comp := consume(c)
+	assert.Equal(t, 6, len(comp))
+	assert.Equal(t, []float64{120, 0, 120}, comp[len(comp)-1].Statistics().Progression(time.Second))
+	assert.Equal(t, []float64{60}, comp[0].Statistics().Progression(time.Second))
+	assert.Nil(t, comp[0].Statistics().Progression(0))
+}
+
+func TestRetypedCharacters(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	// the deleted 'b' doesn't count, only the retyped one does
+	go Compare(stream('a', 'b', 'c'), streamt(start,
+		[]rune{'a', 'b', rune(BS), 'b', 'c'},
+		[]int{0, 500, 1000, 1200, 2500}), c)
+	comp := consume(c)
+	assert.Equal(t, 5, len(comp))
+	stats := comp[len(comp)-1].Statistics()
+	assert.Equal(t, 3, stats.CorrectCharacters())
+	assert.InDelta(t, 3/(2.5/60), stats.RollingCPM(), 0.001)
+	assert.Equal(t, []float64{60, 60, 60}, stats.Progression(time.Second))
+	assert.InDelta(t, 1/(1.0/60), comp[2].Statistics().RollingCPM(), 0.001)
+	// retyping doesn't change the preceding Statistics
+	assert.Equal(t, []float64{120}, comp[1].Statistics().Progression(time.Second))
+}
+
 func compare(t *testing.T, supplier func(i int) interface{}, expected ...interface{}) {
 	match(t, expected, slice(supplier))
 }
@@ -227,6 +319,20 @@ func streamd(elements ...rune) chan Character {
 	return c
 }
 
+// streamt creates and returns a channel of Timed Characters and channels all the
+// given elements into this channel asynchronously. The i-th element is typed
+// offsets[i] milliseconds after start
+func streamt(start time.Time, elements []rune, offsets []int) chan Character {
+	c := make(chan Character, len(elements)/10)
+	go func() {
+		for i, e := range elements {
+			c <- timedchar{char(e), start.Add(time.Duration(offsets[i]) * time.Millisecond)}
+		}
+		close(c)
+	}()
+	return c
+}
+
 // streamuc creates and returns a channel of Characters and channels all the given
 // elements into this channel asynchronously, but won't close the channel
 // afterwards
@@ -273,3 +379,12 @@ type char rune
 func (c char) Rune() rune {
 	return rune(c)
 }
+
+type timedchar struct {
+	char
+	time time.Time
+}
+
+func (c timedchar) Time() time.Time {
+	return c.time
+}
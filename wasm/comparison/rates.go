@@ -0,0 +1,85 @@
+package comparison
+
+import (
+	"time"
+)
+
+// CharactersPerWord is the standardized word-length used for calculating
+// words per minute
+const CharactersPerWord = 5
+
+// Rates contains typing-speeds and the accuracy derived from Statistics and
+// the time passed since the first keystroke
+type Rates interface {
+	// Elapsed returns the duration the Rates are based on
+	Elapsed() time.Duration
+	// CPM returns the amount of correct Characters per minute
+	CPM() float64
+	// GrossWPM returns the amount of typed words per minute, where each word
+	// consists of CharactersPerWord Characters, no matter if they were correct
+	// or not
+	GrossWPM() float64
+	// NetWPM returns the GrossWPM minus the amount of uncorrected errors per
+	// minute. The value is never negative
+	NetWPM() float64
+	// Accuracy returns the share of strokes, that matched their model
+	Accuracy() float64
+}
+
+// RatesAt calculates the Rates for the given Statistics at the given point in
+// time. The elapsed time is measured from the Statistics' first keystroke
+func RatesAt(s Statistics, now time.Time) Rates {
+	if s.Start().IsZero() {
+		return RatesFor(s, 0)
+	}
+	return RatesFor(s, now.Sub(s.Start()))
+}
+
+// RatesFor calculates the Rates for the given Statistics assuming the given
+// duration has passed since the first keystroke
+func RatesFor(s Statistics, elapsed time.Duration) Rates {
+	r := &rates{
+		elapsed:  elapsed,
+		accuracy: 1 - s.FailureRate(),
+	}
+	if elapsed <= 0 {
+		return r
+	}
+	minutes := elapsed.Minutes()
+	r.cpm = float64(s.CorrectCharacters()) / minutes
+	r.grossWPM = float64(s.TotalStrokes()) / CharactersPerWord / minutes
+	uncorrected := float64(s.TotalCharacters()-s.CorrectCharacters()) / minutes
+	r.netWPM = r.grossWPM - uncorrected
+	if r.netWPM < 0 {
+		r.netWPM = 0
+	}
+	return r
+}
+
+type rates struct {
+	elapsed  time.Duration
+	cpm      float64
+	grossWPM float64
+	netWPM   float64
+	accuracy float64
+}
+
+func (r *rates) Elapsed() time.Duration {
+	return r.elapsed
+}
+
+func (r *rates) CPM() float64 {
+	return r.cpm
+}
+
+func (r *rates) GrossWPM() float64 {
+	return r.grossWPM
+}
+
+func (r *rates) NetWPM() float64 {
+	return r.netWPM
+}
+
+func (r *rates) Accuracy() float64 {
+	return r.accuracy
+}
@@ -0,0 +1,55 @@
+package comparison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRates(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	// 10 strokes within 30 seconds, one of them is an uncorrected error
+	go Compare(stream('a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j'), streamt(start,
+		[]rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'x', 'j'},
+		[]int{0, 1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000}), c)
+	comp := consume(c)
+	assert.Equal(t, 10, len(comp))
+	s := comp[len(comp)-1].Statistics()
+	assert.Equal(t, start, s.Start())
+
+	r := RatesAt(s, start.Add(30*time.Second))
+	assert.Equal(t, 30*time.Second, r.Elapsed())
+	assert.InDelta(t, 16, r.CPM(), 0.001)
+	assert.InDelta(t, 4, r.GrossWPM(), 0.001)
+	// uncorrected errors: 'x' and the following 'j'
+	assert.InDelta(t, 0, r.NetWPM(), 0.001)
+	assert.InDelta(t, 0.9, r.Accuracy(), 0.001)
+
+	r = RatesFor(s, 2*time.Minute)
+	assert.InDelta(t, 4, r.CPM(), 0.001)
+	assert.InDelta(t, 1, r.GrossWPM(), 0.001)
+	assert.InDelta(t, 0, r.NetWPM(), 0.001)
+}
+
+func TestRatesNetWPM(t *testing.T) {
+	start := time.Now()
+	c := make(chan Comparison)
+	go Compare(stream('a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j'), streamt(start,
+		[]rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'x'},
+		[]int{0, 1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000}), c)
+	s := consume(c)[9].Statistics()
+	r := RatesFor(s, 30*time.Second)
+	assert.InDelta(t, 4, r.GrossWPM(), 0.001)
+	assert.InDelta(t, 2, r.NetWPM(), 0.001)
+}
+
+func TestRatesWithoutStrokes(t *testing.T) {
+	r := RatesAt(&statistics{}, time.Now())
+	assert.Equal(t, time.Duration(0), r.Elapsed())
+	assert.Equal(t, float64(0), r.CPM())
+	assert.Equal(t, float64(0), r.GrossWPM())
+	assert.Equal(t, float64(0), r.NetWPM())
+	assert.Equal(t, float64(1), r.Accuracy())
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
-	"math/rand"
+	"net/http"
 	"net/url"
+	"sort"
+	"time"
 
 	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"golang.org/x/text/language"
 )
 
 func init() {
@@ -12,23 +18,196 @@ func init() {
 		Scheme: "http",
 		Host:   "localhost:4000",
 	}
-	Game.modificators = make(map[int64]*modificator)
-
+	Backend.Timeout = 10 * time.Second
+	Game = *NewGameConfig()
 }
 
 type GameConfig struct {
-	modificators map[int64]*modificator
+	modificators map[int64]*Modificator
+	lastID       int64
 	sst          api.StreamSourceType
+	source       Source
+	state        *State
 }
 
+// NewGameConfig creates an empty GameConfig
+func NewGameConfig() *GameConfig {
+	return &GameConfig{
+		modificators: make(map[int64]*Modificator),
+		state:        NewState(),
+	}
+}
+
+// Source determines where the model-stream is generated
+type Source int
+
+// sources
+const (
+	// Auto uses the backend, if it is reachable and falls back to Local
+	// otherwise
+	Auto Source = iota
+	// Remote always uses the backend
+	Remote
+	// Local generates the model-stream on the client-side
+	Local
+)
+
 type BackendConfig struct {
 	BaseURL *url.URL
+	// Timeout limits the duration of each request to the backend-api
+	Timeout time.Duration
+	// Transport replaces the backend-api at BaseURL, e.g. by a
+	// communicationtest.Fake in tests
+	Transport communication.Backend
+}
+
+// Client returns a communication.Client for the configured backend-api
+func (bc *BackendConfig) Client() *communication.Client {
+	return communication.NewClient(bc.BaseURL, &http.Client{Timeout: bc.Timeout})
+}
+
+// API returns the configured Transport. If there is none, the Client for the
+// backend-api at BaseURL is returned
+func (bc *BackendConfig) API() communication.Backend {
+	if bc.Transport != nil {
+		return bc.Transport
+	}
+	return bc.Client()
+}
+
+// Description extends the backend's api.StreamSupplierDescription by options,
+// which can only be evaluated by the client
+type Description struct {
+	api.StreamSupplierDescription
+	Dictionary DictionaryOptions
+	End        EndCondition
+	// Weights biases how often the Charset's runes appear in api.Random
+	// streams. Runes without weight have weight 1
+	Weights map[rune]float64
+	// Layout is the ID of the layout.Layout of the user's keyboard
+	Layout string
+}
+
+// KeyboardLayout returns the layout.Layout identified by Layout. It falls back
+// to layout.US, if Layout is unknown
+func (d *Description) KeyboardLayout() *layout.Layout {
+	if l, ok := layout.ByID(d.Layout); ok {
+		return l
+	}
+	return layout.US
+}
+
+// EndCondition determines when a game is over. The game ends as soon as any of
+// the set limits is reached. The zero value never ends the game
+type EndCondition struct {
+	// Duration limits the game's duration measured from the first keystroke
+	Duration time.Duration
+	// Characters limits the amount of typed Characters
+	Characters int
+	// Words limits the amount of correctly typed words
+	Words int
+	// FirstError ends the game with the first miss
+	FirstError bool
+}
+
+// Reached returns whether any of the limits on the amount of characters, words
+// or misses is reached according to s. The Duration has to be enforced
+// separately
+func (e EndCondition) Reached(s comparison.Statistics) bool {
+	return (e.Characters > 0 && s.TotalCharacters() >= e.Characters) ||
+		(e.Words > 0 && s.CorrectWords() >= e.Words) ||
+		(e.FirstError && s.TotalMisses() > 0)
+}
+
+// DictionaryOptions specify which words are picked by api.Dictionary streams
+// and how they are written
+type DictionaryOptions struct {
+	// Language is the dictionary's language. The zero value stands for
+	// language.English
+	Language language.Tag
+	// MinLength and MaxLength limit the words' length. A MaxLength of zero
+	// means, that there is no upper limit
+	MinLength int
+	MaxLength int
+	// MinRank and MaxRank limit the words' frequency-rank, where the most
+	// common word has rank zero. A MaxRank of zero means, that there is no
+	// upper limit
+	MinRank int
+	MaxRank int
+	// Capitalization determines how the words are capitalized
+	Capitalization Capitalization
+}
+
+// Capitalization determines how dictionary-words are capitalized
+type Capitalization int
+
+// capitalizations
+const (
+	// LowerCase writes all words in lower case
+	LowerCase Capitalization = iota
+	// Capitalized writes the first letter of all words in upper case
+	Capitalized
+	// MixedCase writes the first letter of random words in upper case
+	MixedCase
+)
+
+// Stage is a step of the pipeline, that builds the Description. All
+// modificators of a Stage are executed before the ones of the next Stage
+type Stage int
+
+// stages
+const (
+	// Base modificators set up the Description's charset and options
+	Base Stage = iota
+	// Derive modificators extend the result of the Base stage, e.g. by adding
+	// the upper-case-variants of all characters
+	Derive
+	// Final modificators are executed at last and may rely on the complete
+	// charset
+	Final
+)
+
+func (s Stage) String() string {
+	switch s {
+	case Base:
+		return "base"
+	case Derive:
+		return "derive"
+	case Final:
+		return "final"
+	default:
+		return "unknown"
+	}
 }
 
-type modificator struct {
-	sst              api.StreamSourceType
-	isMultiplicative bool
-	action           func(*api.StreamSupplierDescription)
+// Modificator is a step of the pipeline, that builds the Description for a
+// certain api.StreamSourceType. Modificators are executed ordered by Stage,
+// Priority and Name. Modificators, which are equal in all three, are executed
+// in the order they were added
+type Modificator struct {
+	// ID identifies the Modificator. It is assigned by AddModificator
+	ID    int64
+	Type  api.StreamSourceType
+	Name  string
+	Stage Stage
+	// Priority orders the Modificators within a Stage. Lower values are
+	// executed first
+	Priority int
+	Action   func(*Description)
+}
+
+// before returns, whether m is executed before o
+func (m *Modificator) before(o *Modificator) bool {
+	if m.Stage != o.Stage {
+		return m.Stage < o.Stage
+	}
+	if m.Priority != o.Priority {
+		return m.Priority < o.Priority
+	}
+	if m.Name != o.Name {
+		return m.Name < o.Name
+	}
+	return m.ID < o.ID
 }
 
 var Game GameConfig
@@ -37,42 +216,59 @@ var Backend BackendConfig
 // StreamSupplierDescription builds and returns a api.StreamSupplierDescription
 // based on the configured modificators
 func (gc *GameConfig) StreamSupplierDescription() *api.StreamSupplierDescription {
-	ssd := &api.StreamSupplierDescription{
-		Type:    gc.sst,
-		Charset: make([]api.BasicCharacter, 0),
-	}
-	multiplicatives := make([]func(*api.StreamSupplierDescription), 0)
-	for _, m := range gc.modificators {
-		if m.sst == gc.sst {
-			if m.isMultiplicative {
-				multiplicatives = append(multiplicatives, m.action)
-			} else {
-				m.action(ssd)
-			}
-		}
+	return &gc.Description().StreamSupplierDescription
+}
+
+// Description builds and returns a Description based on the configured
+// modificators
+func (gc *GameConfig) Description() *Description {
+	ssd := &Description{
+		StreamSupplierDescription: api.StreamSupplierDescription{
+			Type:    gc.sst,
+			Charset: make([]api.BasicCharacter, 0),
+		},
 	}
-	for _, a := range multiplicatives {
-		a(ssd)
+	for _, m := range gc.pipeline(gc.sst) {
+		m.Action(ssd)
 	}
 	return ssd
 }
 
-// AddModificator registers a function mod, which can modify the
-// StreamSupplierDescription returned by StreamSupplierDescription(), if t
-// matches the api.StreamSourceType currently set via SetType. Functions
-// registered using the multiplicative flag are executed at last
-func (gc *GameConfig) AddModificator(t api.StreamSourceType, mod func(*api.StreamSupplierDescription), multiplicative ...bool) (id int64) {
-	id = rand.Int63()
-	var m bool
-	if len(multiplicative) == 1 {
-		m = multiplicative[0]
+// AddModificator registers the given Modificator, which can modify the
+// Description returned by Description() and StreamSupplierDescription(), if
+// its Type matches the api.StreamSourceType currently set via SetType. The
+// returned id is required for removing the Modificator
+func (gc *GameConfig) AddModificator(m Modificator) (id int64) {
+	gc.lastID++
+	m.ID = gc.lastID
+	gc.modificators[m.ID] = &m
+	return m.ID
+}
+
+// Modificators returns copies of the Modificators registered for the given
+// api.StreamSourceType in the order they are executed
+func (gc *GameConfig) Modificators(t api.StreamSourceType) []Modificator {
+	pipeline := gc.pipeline(t)
+	modificators := make([]Modificator, len(pipeline))
+	for i, m := range pipeline {
+		modificators[i] = *m
 	}
-	gc.modificators[id] = &modificator{
-		sst:              t,
-		isMultiplicative: m,
-		action:           mod,
+	return modificators
+}
+
+// pipeline returns the Modificators registered for the given
+// api.StreamSourceType in the order they are executed
+func (gc *GameConfig) pipeline(t api.StreamSourceType) []*Modificator {
+	pipeline := make([]*Modificator, 0, len(gc.modificators))
+	for _, m := range gc.modificators {
+		if m.Type == t {
+			pipeline = append(pipeline, m)
+		}
 	}
-	return
+	sort.Slice(pipeline, func(i, j int) bool {
+		return pipeline[i].before(pipeline[j])
+	})
+	return pipeline
 }
 
 // RemoveModificator removes the modificator with the given id
@@ -84,4 +280,77 @@ func (gc *GameConfig) RemoveModificator(id int64) {
 // the modificators used
 func (gc *GameConfig) SetType(t api.StreamSourceType) {
 	gc.sst = t
+	gc.state.Type = t
+}
+
+// Type returns the currently configured api.StreamSourceType
+func (gc *GameConfig) Type() api.StreamSourceType {
+	return gc.sst
+}
+
+// SetOption records, whether the option with the given id is enabled for the
+// given api.StreamSourceType. The options themselves are implemented by the
+// caller using modificators; the GameConfig only remembers the user's choice,
+// so it can be persisted using State
+func (gc *GameConfig) SetOption(t api.StreamSourceType, id string, enabled bool) {
+	gc.state.Set(t, id, enabled)
+}
+
+// Option returns, whether the option with the given id is enabled for the
+// given api.StreamSourceType. known is false, if the option was neither set
+// nor restored
+func (gc *GameConfig) Option(t api.StreamSourceType, id string) (enabled, known bool) {
+	return gc.state.Get(t, id)
+}
+
+// SetValue records the value of the option with the given id for the given
+// api.StreamSourceType, so it can be persisted using State
+func (gc *GameConfig) SetValue(t api.StreamSourceType, id, value string) {
+	gc.state.SetValue(t, id, value)
+}
+
+// Value returns the value of the option with the given id for the given
+// api.StreamSourceType. known is false, if the value was neither set nor
+// restored
+func (gc *GameConfig) Value(t api.StreamSourceType, id string) (value string, known bool) {
+	return gc.state.GetValue(t, id)
+}
+
+// State returns a copy of the GameConfig's serializable State
+func (gc *GameConfig) State() *State {
+	return gc.state.copy()
+}
+
+// Restore replaces the recorded type and options by the given State. It does
+// not change any modificators, thus it has to be called before the options
+// are applied
+func (gc *GameConfig) Restore(s *State) {
+	gc.state = s.copy()
+	if s.Type != "" {
+		gc.sst = s.Type
+	}
+}
+
+// SetSource configures where the model-stream is generated
+func (gc *GameConfig) SetSource(s Source) {
+	gc.source = s
+}
+
+// Source returns where the model-stream is generated
+func (gc *GameConfig) Source() Source {
+	return gc.source
+}
+
+// RequiresLocalSource returns whether the Description contains options, which
+// cannot be transmitted to the backend, so the stream has to be generated
+// locally
+func (d *Description) RequiresLocalSource() bool {
+	if d.Type == api.Random && len(d.Weights) > 0 {
+		return true
+	}
+	o := d.Dictionary
+	if o.Language == language.English {
+		o.Language = language.Und
+	}
+	return d.Type == api.Dictionary && o != (DictionaryOptions{})
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+func appending(chars ...api.BasicCharacter) func(*Description) {
+	return func(d *Description) {
+		d.Charset = append(d.Charset, chars...)
+	}
+}
+
+func names(ms []Modificator) []string {
+	n := make([]string, len(ms))
+	for i, m := range ms {
+		n[i] = m.Name
+	}
+	return n
+}
+
+func TestModificatorOrder(t *testing.T) {
+	gc := NewGameConfig()
+	gc.SetType(api.Random)
+	gc.AddModificator(Modificator{Type: api.Random, Name: "final", Stage: Final, Action: appending('f')})
+	gc.AddModificator(Modificator{Type: api.Random, Name: "derive", Stage: Derive, Action: appending('d')})
+	gc.AddModificator(Modificator{Type: api.Random, Name: "b", Stage: Base, Action: appending('b')})
+	gc.AddModificator(Modificator{Type: api.Random, Name: "a", Stage: Base, Action: appending('a')})
+	gc.AddModificator(Modificator{Type: api.Random, Name: "z", Stage: Base, Priority: -1, Action: appending('z')})
+	gc.AddModificator(Modificator{Type: api.Dictionary, Name: "other", Stage: Base, Action: appending('o')})
+
+	assert.Equal(t, []string{"z", "a", "b", "derive", "final"}, names(gc.Modificators(api.Random)))
+	assert.Equal(t, []string{"other"}, names(gc.Modificators(api.Dictionary)))
+	assert.Equal(t, []api.BasicCharacter{'z', 'a', 'b', 'd', 'f'}, gc.Description().Charset)
+	assert.Equal(t, api.Random, gc.StreamSupplierDescription().Type)
+}
+
+func TestModificatorRemoval(t *testing.T) {
+	gc := NewGameConfig()
+	gc.SetType(api.Random)
+	a := gc.AddModificator(Modificator{Type: api.Random, Name: "a", Action: appending('a')})
+	b := gc.AddModificator(Modificator{Type: api.Random, Name: "a", Action: appending('b')})
+	assert.NotEqual(t, a, b)
+	// equal modificators are executed in the order they were added
+	assert.Equal(t, []api.BasicCharacter{'a', 'b'}, gc.Description().Charset)
+	assert.Equal(t, a, gc.Modificators(api.Random)[0].ID)
+
+	gc.RemoveModificator(a)
+	assert.Equal(t, []api.BasicCharacter{'b'}, gc.Description().Charset)
+	gc.RemoveModificator(b)
+	assert.Empty(t, gc.Modificators(api.Random))
+	assert.Empty(t, gc.Description().Charset)
+}
+
+func TestModificatorDeterminism(t *testing.T) {
+	modificators := []Modificator{
+		{Type: api.Random, Name: "charset-inner", Stage: Base, Action: appending('a', 's')},
+		{Type: api.Random, Name: "charset-thumbs", Stage: Base, Action: appending(' ')},
+		{Type: api.Random, Name: "charset-indexfingers", Stage: Base, Action: appending('f', 'j')},
+		{Type: api.Random, Name: "shift", Stage: Derive, Action: func(d *Description) {
+			for _, c := range d.Charset {
+				if c != ' ' {
+					d.Charset = append(d.Charset, c-'a'+'A')
+				}
+			}
+		}},
+	}
+	var expected *Description
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		gc := NewGameConfig()
+		gc.SetType(api.Random)
+		for _, j := range r.Perm(len(modificators)) {
+			gc.AddModificator(modificators[j])
+		}
+		d := gc.Description()
+		if expected == nil {
+			expected = d
+		}
+		assert.Equal(t, expected, d)
+	}
+	assert.Equal(t, []api.BasicCharacter{'f', 'j', 'a', 's', ' ', 'F', 'J', 'A', 'S'}, expected.Charset)
+}
+
+// statistics returns the final Statistics of typing attempt on model
+func statistics(model, attempt string) comparison.Statistics {
+	m := make(chan comparison.Character, len(model))
+	for _, r := range model {
+		m <- api.BasicCharacter(r)
+	}
+	a := make(chan comparison.Character, len(attempt))
+	for _, r := range attempt {
+		a <- api.BasicCharacter(r)
+	}
+	close(a)
+	cmp := make(chan comparison.Comparison)
+	go comparison.Compare(m, a, cmp)
+	var s comparison.Statistics
+	for c := range cmp {
+		s = c.Statistics()
+	}
+	return s
+}
+
+func TestEndCondition(t *testing.T) {
+	correct := statistics("ab cd ef", "ab cd")
+	wrong := statistics("ab cd ef", "ab x")
+
+	assert.False(t, EndCondition{}.Reached(correct))
+	assert.False(t, EndCondition{Duration: time.Second}.Reached(correct))
+	assert.True(t, EndCondition{Characters: 5}.Reached(correct))
+	assert.False(t, EndCondition{Characters: 6}.Reached(correct))
+	assert.True(t, EndCondition{Words: 1}.Reached(correct))
+	assert.False(t, EndCondition{Words: 2}.Reached(correct))
+	assert.False(t, EndCondition{FirstError: true}.Reached(correct))
+	assert.True(t, EndCondition{FirstError: true}.Reached(wrong))
+	assert.True(t, EndCondition{Words: 10, FirstError: true}.Reached(wrong))
+}
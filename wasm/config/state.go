@@ -0,0 +1,219 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// StateVersion is the current version of the State-schema. It has to be
+// increased, whenever the meaning of stored options changes. In this case, a
+// migration from the previous version has to be added to migrations
+const StateVersion = 1
+
+// errors
+var (
+	ErrIllegalState        = errors.New("the stored configuration is invalid", errors.Warning, errors.Input)
+	ErrUnknownStateVersion = errors.New("the stored configuration was created by a newer version", errors.Warning, errors.Input)
+)
+
+// migrations contains functions, which convert a State of version i to
+// version i+1. The first version is 1
+var migrations = map[int]func(*State){}
+
+// State is the serializable part of a GameConfig. It holds the selected
+// StreamSourceType, whether the options are enabled or disabled and the values
+// of configurable options, identified by their StreamSourceType and id
+type State struct {
+	Version int                                        `json:"version"`
+	Type    api.StreamSourceType                       `json:"type"`
+	Options map[api.StreamSourceType]map[string]bool   `json:"options"`
+	Values  map[api.StreamSourceType]map[string]string `json:"values,omitempty"`
+	// complete contains the StreamSourceTypes, for which all enabled options
+	// are known, so options, that were not recorded are disabled
+	complete map[api.StreamSourceType]bool
+}
+
+// queryKeyType is the url query-parameter, which holds the State's Type
+const queryKeyType = "type"
+
+// queryValueSeparator separates the StreamSourceType from the option's id in
+// the url query-parameters holding values
+const queryValueSeparator = "."
+
+// NewState creates an empty State of the current version
+func NewState() *State {
+	return &State{
+		Version:  StateVersion,
+		Options:  make(map[api.StreamSourceType]map[string]bool),
+		Values:   make(map[api.StreamSourceType]map[string]string),
+		complete: make(map[api.StreamSourceType]bool),
+	}
+}
+
+// DecodeState parses a State encoded by State.Encode and migrates it to the
+// current StateVersion. A State without a version is illegal
+func DecodeState(data []byte) (*State, errors.Error) {
+	return decodeState(data, StateVersion)
+}
+
+// decodeState parses a State encoded by State.Encode and migrates it to the
+// given version
+func decodeState(data []byte, version int) (*State, errors.Error) {
+	s := &State{}
+	err := json.Unmarshal(data, s)
+	if err != nil {
+		return nil, ErrIllegalState.Append(err.Error())
+	}
+	if s.Version > version {
+		return nil, ErrUnknownStateVersion.Append(strconv.Itoa(s.Version))
+	}
+	if s.Version <= 0 {
+		return nil, ErrIllegalState.Append("missing version")
+	}
+	for s.Version < version {
+		if m, ok := migrations[s.Version]; ok {
+			m(s)
+		}
+		s.Version++
+	}
+	if s.Options == nil {
+		s.Options = make(map[api.StreamSourceType]map[string]bool)
+	}
+	if s.Values == nil {
+		s.Values = make(map[api.StreamSourceType]map[string]string)
+	}
+	s.complete = make(map[api.StreamSourceType]bool)
+	return s, nil
+}
+
+// StateFromQuery parses a State encoded by State.Query. As the query lists all
+// enabled options of a StreamSourceType, all options of the contained
+// StreamSourceTypes, that are not listed, are reported as disabled by Get.
+// ok is false, if the query doesn't contain any configuration
+func StateFromQuery(q url.Values) (s *State, ok bool) {
+	s = NewState()
+	for k, v := range q {
+		if len(v) == 0 {
+			continue
+		}
+		if k == queryKeyType {
+			s.Type = api.StreamSourceType(v[0])
+			ok = true
+			continue
+		}
+		if k == "" || strings.ToLower(k[:1]) == k[:1] {
+			// StreamSourceTypes start with an upper-case-letter; other
+			// parameters are not part of the State
+			continue
+		}
+		if i := strings.Index(k, queryValueSeparator); i >= 0 {
+			s.SetValue(api.StreamSourceType(k[:i]), k[i+len(queryValueSeparator):], v[0])
+			ok = true
+			continue
+		}
+		t := api.StreamSourceType(k)
+		s.complete[t] = true
+		for _, id := range strings.Split(v[0], ",") {
+			if id != "" {
+				s.Set(t, id, true)
+			}
+		}
+		ok = true
+	}
+	return s, ok
+}
+
+// Query encodes the State as url query-parameters. The Type is stored under
+// the key "type" and the enabled options of each StreamSourceType are listed
+// comma-separated under the StreamSourceType's name. Values are stored under
+// the StreamSourceType's name and the option's id separated by a dot
+func (s *State) Query() url.Values {
+	q := url.Values{}
+	if s.Type != "" {
+		q.Set(queryKeyType, string(s.Type))
+	}
+	for t, options := range s.Options {
+		enabled := make([]string, 0, len(options))
+		for id, e := range options {
+			if e {
+				enabled = append(enabled, id)
+			}
+		}
+		sort.Strings(enabled)
+		q.Set(string(t), strings.Join(enabled, ","))
+	}
+	for t, values := range s.Values {
+		for id, v := range values {
+			q.Set(string(t)+queryValueSeparator+id, v)
+		}
+	}
+	return q
+}
+
+// Encode serializes the State
+func (s *State) Encode() []byte {
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// Set records, whether the option with the given id is enabled for the given
+// StreamSourceType
+func (s *State) Set(t api.StreamSourceType, id string, enabled bool) {
+	if s.Options[t] == nil {
+		s.Options[t] = make(map[string]bool)
+	}
+	s.Options[t][id] = enabled
+}
+
+// Get returns, whether the option with the given id is enabled for the given
+// StreamSourceType. known is false, if the option was never recorded
+func (s *State) Get(t api.StreamSourceType, id string) (enabled, known bool) {
+	enabled, known = s.Options[t][id]
+	if !known && s.complete[t] {
+		return false, true
+	}
+	return
+}
+
+// SetValue records the value of the option with the given id for the given
+// StreamSourceType
+func (s *State) SetValue(t api.StreamSourceType, id, value string) {
+	if s.Values[t] == nil {
+		s.Values[t] = make(map[string]string)
+	}
+	s.Values[t][id] = value
+}
+
+// GetValue returns the value of the option with the given id for the given
+// StreamSourceType. known is false, if the value was never recorded
+func (s *State) GetValue(t api.StreamSourceType, id string) (value string, known bool) {
+	value, known = s.Values[t][id]
+	return
+}
+
+// copy returns a deep copy of the State
+func (s *State) copy() *State {
+	c := NewState()
+	c.Version = s.Version
+	c.Type = s.Type
+	for t, options := range s.Options {
+		for id, enabled := range options {
+			c.Set(t, id, enabled)
+		}
+	}
+	for t, values := range s.Values {
+		for id, v := range values {
+			c.SetValue(t, id, v)
+		}
+	}
+	for t, complete := range s.complete {
+		c.complete[t] = complete
+	}
+	return c
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+)
+
+func TestStateEncoding(t *testing.T) {
+	s := NewState()
+	s.Type = api.Dictionary
+	s.Set(api.Random, "shift", false)
+	s.Set(api.Dictionary, "language-de", true)
+	s.SetValue(api.Random, "duration-custom", "90")
+
+	d, err := DecodeState(s.Encode())
+	assert.Nil(t, err)
+	assert.Equal(t, s, d)
+	enabled, known := d.Get(api.Random, "shift")
+	assert.False(t, enabled)
+	assert.True(t, known)
+	_, known = d.Get(api.Random, "inner")
+	assert.False(t, known)
+}
+
+func TestStateQuery(t *testing.T) {
+	s := NewState()
+	s.Type = api.Random
+	s.Set(api.Random, "inner", true)
+	s.Set(api.Random, "thumbs", true)
+	s.Set(api.Random, "shift", false)
+	s.Set(api.Dictionary, "language-de", true)
+	s.SetValue(api.Random, "duration-custom", "90")
+
+	q := s.Query()
+	assert.Equal(t, "Random", q.Get("type"))
+	assert.Equal(t, "inner,thumbs", q.Get("Random"))
+	q.Set("lang", "de")
+
+	d, ok := StateFromQuery(q)
+	assert.True(t, ok)
+	assert.Equal(t, api.Random, d.Type)
+	v, known := d.GetValue(api.Random, "duration-custom")
+	assert.Equal(t, "90", v)
+	assert.True(t, known)
+	enabled, known := d.Get(api.Random, "thumbs")
+	assert.True(t, enabled)
+	assert.True(t, known)
+	// options missing in the query are disabled
+	enabled, known = d.Get(api.Random, "indexfingers")
+	assert.False(t, enabled)
+	assert.True(t, known)
+	enabled, known = d.Get(api.Dictionary, "language-en")
+	assert.False(t, enabled)
+	assert.True(t, known)
+	// types missing in the query are unknown
+	_, known = d.Get(api.StreamSourceType("Other"), "inner")
+	assert.False(t, known)
+	_, known = d.copy().Get(api.Random, "indexfingers")
+	assert.True(t, known)
+
+	_, ok = StateFromQuery(url.Values{"lang": {"de"}})
+	assert.False(t, ok)
+}
+
+func TestStateVersion(t *testing.T) {
+	_, err := DecodeState([]byte(`{"type":"Random"}`))
+	assert.Equal(t, ErrIllegalState.Type(), err.Type())
+	_, err = DecodeState([]byte(`{"version":0,"type":"Random"}`))
+	assert.Equal(t, ErrIllegalState.Type(), err.Type())
+	_, err = DecodeState([]byte(`{"version":999999,"type":"Random"}`))
+	assert.Equal(t, ErrUnknownStateVersion.Type(), err.Type())
+	_, err = DecodeState([]byte(`not json`))
+	assert.Equal(t, ErrIllegalState.Type(), err.Type())
+}
+
+func TestStateMigration(t *testing.T) {
+	defer func(m map[int]func(*State)) {
+		migrations = m
+	}(migrations)
+	// version 2 renames the Random-option "inner" to "home"
+	migrations = map[int]func(*State){
+		1: func(s *State) {
+			if enabled, known := s.Get(api.Random, "inner"); known {
+				s.Set(api.Random, "home", enabled)
+				delete(s.Options[api.Random], "inner")
+			}
+		},
+	}
+	s := NewState()
+	s.Type = api.Random
+	s.Set(api.Random, "inner", true)
+	s.Set(api.Random, "shift", false)
+	s.SetValue(api.Random, "duration-custom", "90")
+	v1 := s.Encode()
+
+	d, err := decodeState(v1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, d.Version)
+	assert.Equal(t, api.Random, d.Type)
+	enabled, known := d.Get(api.Random, "home")
+	assert.True(t, enabled)
+	assert.True(t, known)
+	_, known = d.Get(api.Random, "inner")
+	assert.False(t, known)
+	// the other fields are kept
+	enabled, known = d.Get(api.Random, "shift")
+	assert.False(t, enabled)
+	assert.True(t, known)
+	v, _ := d.GetValue(api.Random, "duration-custom")
+	assert.Equal(t, "90", v)
+
+	// the current version isn't migrated
+	d, err = DecodeState(v1)
+	assert.Nil(t, err)
+	_, known = d.Get(api.Random, "inner")
+	assert.True(t, known)
+}
+
+func TestGameConfigState(t *testing.T) {
+	gc := NewGameConfig()
+	gc.SetType(api.Random)
+	gc.SetOption(api.Random, "inner", false)
+	s := gc.State()
+	// modifying the copy doesn't affect the GameConfig
+	s.Set(api.Random, "inner", true)
+	enabled, _ := gc.Option(api.Random, "inner")
+	assert.False(t, enabled)
+
+	restored := NewGameConfig()
+	restored.Restore(gc.State())
+	assert.Equal(t, api.Random, restored.Type())
+	enabled, known := restored.Option(api.Random, "inner")
+	assert.False(t, enabled)
+	assert.True(t, known)
+}
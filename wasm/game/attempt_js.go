@@ -0,0 +1,48 @@
+//go:build js && wasm
+// +build js,wasm
+
+package game
+
+import (
+	"sort"
+	"syscall/js"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+)
+
+// AttemptInputProvider returns a Provider, which registers an EventListener
+// for the given charset and pipes the events into its stream asynchronously.
+// If the given charset is nil, any key is accepted. In addition all callbacks
+// are called asynchronously after each input. The EventListener is removed,
+// when the Session stops. The stream is never closed, as the EventListener
+// might still be running
+func AttemptInputProvider(charset []api.Character, callbacks ...func(api.Character)) Provider {
+	sort.Slice(charset, func(i, j int) bool {
+		return charset[i].Rune() < charset[j].Rune()
+	})
+	return func(done <-chan bool) <-chan comparison.Character {
+		apt := make(chan comparison.Character, 5)
+		listener := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			c := api.BasicCharacter(args[0].Get("charCode").Int())
+			if charset == nil || contains(charset, c) {
+				select {
+				case apt <- c:
+				case <-done:
+					return nil
+				}
+				for _, f := range callbacks {
+					go f(c)
+				}
+			}
+			return nil
+		})
+		js.Global().Call("addEventListener", "keypress", listener)
+		go func() {
+			<-done
+			js.Global().Call("removeEventListener", "keypress", listener)
+			listener.Release()
+		}()
+		return apt
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Ghost replays a Recording alongside a running game, so the user can race a
+// previous run on the same model-text. All methods are safe for concurrent use
+type Ghost struct {
+	replay    *Replay
+	session   *Session
+	positions chan int
+}
+
+// NewGhost creates a Ghost, which replays the given Recording at its original
+// speed
+func NewGhost(r *Recording) *Ghost {
+	replay := NewReplay(r, 1)
+	return &Ghost{
+		replay:    replay,
+		session:   NewSession(config.EndCondition{}, replay.Model(), replay.Attempt()),
+		positions: make(chan int),
+	}
+}
+
+// Positions returns the stream of the Ghost's positions, i.e. the index of
+// its next character within the model-text. It is closed after the Ghost has
+// stopped. Failures of the Ghost aren't reported. The stream must be consumed
+// until it is closed
+func (g *Ghost) Positions() <-chan int {
+	return g.positions
+}
+
+// Start runs the Ghost asynchronously. It should be called, when the user
+// types the first character. If the Ghost was started before,
+// ErrAlreadyRunning is returned
+func (g *Ghost) Start() errors.Error {
+	if err := g.session.Start(); err != nil {
+		return err
+	}
+	go func() {
+		defer close(g.positions)
+		for e := range g.session.Events() {
+			if e.Type == Compared && len(e.Comparison.Changes()) > 0 {
+				g.positions <- position(e.Comparison.Changes()[len(e.Comparison.Changes())-1])
+			}
+		}
+	}()
+	return nil
+}
+
+// Pause freezes the Ghost until Resume is called
+func (g *Ghost) Pause() {
+	g.replay.Pause()
+}
+
+// Resume continues a paused Ghost
+func (g *Ghost) Resume() {
+	g.replay.Resume()
+}
+
+// Stop ends the Ghost. Stopping a stopped Ghost has no effect
+func (g *Ghost) Stop() {
+	g.session.Stop()
+}
+
+// position returns the index of the next expected character after the given
+// Modification
+func position(m comparison.Modification) int {
+	if m.Deletion() {
+		return m.Position()
+	}
+	return m.Position() + 1
+}
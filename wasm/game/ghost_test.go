@@ -0,0 +1,39 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGhost(t *testing.T) {
+	g := NewGhost(&Recording{
+		Model: []rune("abc"),
+		Keystrokes: []Keystroke{
+			{'a', 0},
+			{'x', 10 * time.Millisecond},
+			{'\b', 20 * time.Millisecond},
+			{'b', 30 * time.Millisecond},
+		},
+	})
+	assert.Nil(t, g.Start())
+	assert.Equal(t, ErrAlreadyRunning, g.Start())
+	var positions []int
+	for p := range g.Positions() {
+		positions = append(positions, p)
+	}
+	assert.Equal(t, []int{1, 2, 1, 2}, positions)
+}
+
+func TestGhostStop(t *testing.T) {
+	g := NewGhost(&Recording{
+		Model:      []rune("ab"),
+		Keystrokes: []Keystroke{{'a', 0}, {'b', time.Hour}},
+	})
+	g.Start()
+	assert.Equal(t, 1, <-g.Positions())
+	g.Stop()
+	_, ok := <-g.Positions()
+	assert.False(t, ok)
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"context"
+	"sort"
+
+	"github.com/theMomax/notypo-backend/api"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+	"github.com/theMomax/notypo-frontend/wasm/streams"
+)
+
+// ModelInputProvider returns a Provider, which creates and subscribes to a
+// Character-Stream using the backend-api and the given description. If the
+// description contains options the backend doesn't support, if the configured
+// config.Source demands it, or if the backend is unreachable in config.Auto
+// mode, the stream is generated locally instead. Pending requests are canceled,
+// when done is closed. A lost connection to the backend is reestablished, while
+// warn is notified as described by com.Backend.ReadStream. The Provider panics,
+// if the server responses with a critical error, or, if description is invalid
+func ModelInputProvider(description *config.Description, warn func(errors.Error)) Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		ctx := contextOf(done)
+		if description.RequiresLocalSource() || UseLocalSource(ctx) {
+			mod, err := streams.Open(description, 50, done)
+			if err != nil {
+				panic(err)
+			}
+			return mod
+		}
+
+		streamID, err := config.Backend.API().CreateRandomStream(ctx, &description.StreamSupplierDescription)
+		if err != nil {
+			return failed(err)
+		}
+		return openStream(ctx, *streamID, warn)
+	}
+}
+
+// SharedInputProvider returns a Provider, which subscribes to the existing
+// Character-Stream with the given id, e.g. the model-stream shared by all
+// players of a Race. A lost connection is reestablished like the one of
+// ModelInputProvider. The Provider panics, if the server responses with a
+// critical error
+func SharedInputProvider(streamID int64, warn func(errors.Error)) Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		return openStream(contextOf(done), streamID, warn)
+	}
+}
+
+// openStream reads the Stream with the given id, until ctx is done. It panics,
+// if the server responses with a critical error
+func openStream(ctx context.Context, streamID int64, warn func(errors.Error)) <-chan comparison.Character {
+	mod, err := config.Backend.API().ReadStream(ctx, 50, streamID, warn)
+	if err != nil {
+		return failed(err)
+	}
+	return mod
+}
+
+// contextOf returns a context.Context, which is canceled, when done is closed
+func contextOf(done <-chan bool) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-done
+		cancel()
+	}()
+	return ctx
+}
+
+// failed panics with the given error. If the request was canceled, because the
+// Session was stopped, an empty stream is returned instead
+func failed(err errors.Error) <-chan comparison.Character {
+	if err.Type() != com.ErrCanceled.Type() {
+		panic(err)
+	}
+	c := make(chan comparison.Character)
+	close(c)
+	return c
+}
+
+// RepeatInputProvider returns a Provider, whose stream starts with the given
+// text and continues with the Characters read from the stream opened by rest.
+// It is closed, when rest's stream is closed
+func RepeatInputProvider(text []comparison.Character, rest Provider) Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		r := rest(done)
+		mod := make(chan comparison.Character, cap(r))
+		go func() {
+			defer close(mod)
+			for _, c := range text {
+				select {
+				case mod <- c:
+				case <-done:
+					return
+				}
+			}
+			for c := range r {
+				select {
+				case mod <- c:
+				case <-done:
+					return
+				}
+			}
+		}()
+		return mod
+	}
+}
+
+// UseLocalSource returns whether model-streams are generated locally according
+// to config.Game's Source. In config.Auto mode, the backend's reachability is
+// checked using com.Backend.Version, which is canceled, when ctx is done
+func UseLocalSource(ctx context.Context) bool {
+	switch config.Game.Source() {
+	case config.Local:
+		return true
+	case config.Remote:
+		return false
+	}
+	_, err := config.Backend.API().Version(ctx)
+	return err != nil && err.Type() == com.ErrServerConnectionFailed.Type()
+}
+
+func contains(s []api.Character, c api.Character) bool {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].Rune() >= c.Rune()
+	})
+	if i < len(s) && s[i].Rune() == c.Rune() {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	comtest "github.com/theMomax/notypo-frontend/wasm/communication/communicationtest"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// useFake makes the Providers use a Fake, whose Streams emit the given text,
+// until the returned function is called. The Streams are closed afterwards,
+// if closed is true
+func useFake(text string, closed bool) (*comtest.Fake, func()) {
+	f := comtest.NewFake(comtest.Text(text))
+	f.Closed = closed
+	source := config.Game.Source()
+	config.Backend.Transport = f
+	config.Game.SetSource(config.Auto)
+	return f, func() {
+		config.Backend.Transport = nil
+		config.Game.SetSource(source)
+	}
+}
+
+func randomDescription() *config.Description {
+	d := &config.Description{}
+	d.Type = api.Random
+	d.Charset = []api.BasicCharacter{'a', 'b', 'c'}
+	return d
+}
+
+// modelText returns the Characters of the given ModelCharacter Events
+func modelText(events []Event) string {
+	var r []rune
+	for _, e := range events {
+		r = append(r, e.Character.Rune())
+	}
+	return string(r)
+}
+
+// released fails, if f still has open connections after a second
+func released(t *testing.T, f *comtest.Fake) {
+	timeout := time.After(time.Second)
+	for f.Connections() > 0 {
+		select {
+		case <-timeout:
+			t.Fatal("the connections weren't closed")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestModelInputProvider(t *testing.T) {
+	f, restore := useFake("abcdef", false)
+	defer restore()
+	attempt := make(chan comparison.Character, 2)
+	attempt <- api.BasicCharacter('a')
+	attempt <- api.BasicCharacter('x')
+	s := NewSession(config.EndCondition{Characters: 2}, ModelInputProvider(randomDescription(), nil), channel(attempt))
+	s.Start()
+	events := collect(t, s)
+	assert.Empty(t, events[Failed])
+	if assert.Equal(t, 2, len(events[Compared])) {
+		stats := events[Compared][1].Comparison.Statistics()
+		assert.Equal(t, 2, stats.TotalCharacters())
+		assert.Equal(t, 1, stats.TotalMisses())
+	}
+	if assert.Equal(t, 1, len(f.Descriptions())) {
+		assert.Equal(t, randomDescription().StreamSupplierDescription, f.Descriptions()[0])
+	}
+	released(t, f)
+}
+
+func TestModelInputProviderCancel(t *testing.T) {
+	f, restore := useFake("abc", false)
+	defer restore()
+	// the backend hangs, until the user leaves the game
+	f.Latency = time.Hour
+	s := NewSession(config.EndCondition{}, ModelInputProvider(randomDescription(), nil), channel(make(chan comparison.Character)))
+	s.Start()
+	time.AfterFunc(10*time.Millisecond, s.Stop)
+	events := collect(t, s)
+	assert.Empty(t, events[Failed])
+	assert.Empty(t, events[ModelCharacter])
+}
+
+func TestModelInputProviderFailure(t *testing.T) {
+	f, restore := useFake("abc", false)
+	defer restore()
+	f.Fail(comtest.CallCreateRandomStream, com.ErrIllegalConfiguration)
+	s := NewSession(config.EndCondition{}, ModelInputProvider(randomDescription(), nil), channel(make(chan comparison.Character)))
+	s.Start()
+	events := collect(t, s)
+	if assert.Equal(t, 1, len(events[Failed])) {
+		assert.Equal(t, com.ErrIllegalConfiguration, events[Failed][0].Err)
+	}
+}
+
+func TestModelInputProviderLocalFallback(t *testing.T) {
+	f, restore := useFake("abc", false)
+	defer restore()
+	f.Fail(comtest.CallVersion, com.ErrServerConnectionFailed)
+	s := NewSession(config.EndCondition{}, ModelInputProvider(randomDescription(), nil), channel(make(chan comparison.Character)))
+	s.Start()
+	e := <-s.Events()
+	assert.Equal(t, ModelCharacter, e.Type)
+	assert.Contains(t, "abc", string(e.Character.Rune()))
+	s.Stop()
+	collect(t, s)
+	assert.Empty(t, f.Descriptions())
+}
+
+func TestModelInputProviderReconnect(t *testing.T) {
+	f, restore := useFake("abcdef", true)
+	defer restore()
+	f.DropAfter = 2
+	var mutex sync.Mutex
+	var warnings []errors.Error
+	warn := func(err errors.Error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		warnings = append(warnings, err)
+	}
+	s := NewSession(config.EndCondition{}, ModelInputProvider(randomDescription(), warn), channel(make(chan comparison.Character)))
+	s.Start()
+	events := collect(t, s)
+	assert.Equal(t, "abcdef", modelText(events[ModelCharacter]))
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []errors.Error{com.ErrReconnecting, nil}, warnings)
+	released(t, f)
+}
+
+func TestSharedInputProvider(t *testing.T) {
+	f, restore := useFake("", false)
+	defer restore()
+	id := f.AddStream("abc")
+	// all players receive the same text
+	var sessions []*Session
+	for i := 0; i < 2; i++ {
+		s := NewSession(config.EndCondition{}, SharedInputProvider(id, nil), channel(make(chan comparison.Character)))
+		s.Start()
+		sessions = append(sessions, s)
+	}
+	for _, s := range sessions {
+		var events []Event
+		for len(events) < 3 {
+			events = append(events, <-s.Events())
+		}
+		assert.Equal(t, "abc", modelText(events))
+	}
+	for _, s := range sessions {
+		s.Stop()
+		collect(t, s)
+	}
+	// the Stream is deleted, after the last player left
+	released(t, f)
+	_, err := f.OpenStreamConnection(context.Background(), id)
+	assert.Equal(t, com.ErrStreamNotFound, err)
+}
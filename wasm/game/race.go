@@ -0,0 +1,137 @@
+package game
+
+import (
+	"sync"
+	"time"
+
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Opponents connects a Race to the other players, e.g. a communication.Lobby
+type Opponents interface {
+	Report(com.Progress) errors.Error
+	Messages() <-chan com.Message
+}
+
+// Race is a multiplayer-game, where all players type the same model-text. The
+// slowest players are eliminated over time, until only the fastest one
+// survives. All methods are safe for concurrent use
+type Race struct {
+	player    string
+	opponents Opponents
+	session   *Session
+	interval  time.Duration
+	events    chan Event
+	stop      chan bool
+	once      sync.Once
+}
+
+// NewRace creates a Race of the given player against the given Opponents. The
+// player's Session compares the streams opened by model and attempt. Its
+// Progress is reported once per interval
+func NewRace(player string, opponents Opponents, model, attempt Provider, interval time.Duration) *Race {
+	return &Race{
+		player:    player,
+		opponents: opponents,
+		session:   NewSession(config.EndCondition{}, model, attempt),
+		interval:  interval,
+		events:    make(chan Event),
+		stop:      make(chan bool),
+	}
+}
+
+// Events returns the stream of the Race's Events, i.e. the Events of the
+// player's Session as well as Standings, Eliminated and Finished Events. The
+// player's Session ends, when they are eliminated or the Race is finished. The
+// stream is closed after the Race is finished, the connection to the
+// Opponents is lost, or the Race is stopped. It must be consumed until it is
+// closed
+func (r *Race) Events() <-chan Event {
+	return r.events
+}
+
+// Start runs the Race asynchronously. If the Race was started before,
+// ErrAlreadyRunning is returned
+func (r *Race) Start() errors.Error {
+	if err := r.session.Start(); err != nil {
+		return err
+	}
+	go r.run()
+	return nil
+}
+
+// Elapsed returns the time passed since the player's first keystroke, just
+// like Session.Elapsed, so the player's Rates are comparable to the ones of
+// single-player-games
+func (r *Race) Elapsed() time.Duration {
+	return r.session.Elapsed()
+}
+
+// Stop leaves the Race. Stopping a stopped Race has no effect
+func (r *Race) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *Race) run() {
+	defer close(r.events)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var stats comparison.Statistics
+	report := func() {
+		// a lost connection is reported, when the Messages are closed
+		if stats != nil {
+			r.opponents.Report(com.ProgressOf(stats, r.session.Elapsed()))
+		}
+	}
+	events := r.session.Events()
+	messages := r.opponents.Messages()
+	tick := ticker.C
+	stop := r.stop
+	for events != nil || messages != nil {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				report()
+				events, tick = nil, nil
+				continue
+			}
+			if e.Type == Compared {
+				stats = e.Comparison.Statistics()
+			}
+			r.events <- e
+		case <-tick:
+			report()
+		case m, ok := <-messages:
+			if !ok {
+				r.session.Stop()
+				messages = nil
+				r.events <- Event{Type: Failed, Err: com.ErrLobbyConnectionLost}
+				continue
+			}
+			switch m.Type {
+			case com.StandingsUpdated:
+				r.events <- Event{Type: Standings, Players: m.Players}
+			case com.PlayerEliminated:
+				if m.Player == r.player {
+					r.session.Stop()
+				}
+				r.events <- Event{Type: Eliminated, Player: m.Player}
+			case com.RaceFinished:
+				r.session.Stop()
+				messages = nil
+				r.events <- Event{Type: Finished, Player: m.Player}
+			case com.LobbyFailure:
+				r.events <- Event{Type: Failed, Err: m.Err()}
+			}
+		case <-stop:
+			r.session.Stop()
+			messages, stop = nil, nil
+		}
+	}
+}
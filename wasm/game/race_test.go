@@ -0,0 +1,113 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// opponents is a fake lobby, whose Messages are sent by the tests
+type opponents struct {
+	messages chan com.Message
+	reports  chan com.Progress
+}
+
+func newOpponents() *opponents {
+	return &opponents{
+		messages: make(chan com.Message),
+		reports:  make(chan com.Progress, 100),
+	}
+}
+
+func (o *opponents) Report(p com.Progress) errors.Error {
+	o.reports <- p
+	return nil
+}
+
+func (o *opponents) Messages() <-chan com.Message {
+	return o.messages
+}
+
+// await returns the next Event of the given type
+func await(t *testing.T, r *Race, typ EventType) Event {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e, ok := <-r.Events():
+			if !ok {
+				t.Fatal("the race has ended")
+			}
+			if e.Type == typ {
+				return e
+			}
+		case <-timeout:
+			t.Fatal("no event received")
+		}
+	}
+}
+
+func TestRace(t *testing.T) {
+	o := newOpponents()
+	attempt := make(chan comparison.Character, 2)
+	r := NewRace("alice", o, stream("abc", false), channel(attempt), 10*time.Millisecond)
+	assert.Nil(t, r.Start())
+	// the time is measured from the first keystroke
+	assert.Equal(t, time.Duration(0), r.Elapsed())
+
+	attempt <- api.BasicCharacter('a')
+	attempt <- api.BasicCharacter('x')
+	await(t, r, Compared)
+	await(t, r, Compared)
+	// the progress is reported periodically, while the remaining Events are
+	// consumed
+	var p com.Progress
+	for p.Characters < 2 {
+		select {
+		case p = <-o.reports:
+		case <-r.Events():
+		}
+	}
+	assert.Equal(t, 1, p.CorrectCharacters)
+	assert.Equal(t, 1, p.Misses)
+
+	standings := []com.Player{{Name: "bob"}, {Name: "alice"}}
+	o.messages <- com.Message{Type: com.StandingsUpdated, Players: standings}
+	assert.Equal(t, standings, await(t, r, Standings).Players)
+
+	// the player can watch the race after being eliminated
+	o.messages <- com.Message{Type: com.PlayerEliminated, Player: "alice", Round: 1}
+	assert.Equal(t, "alice", await(t, r, Eliminated).Player)
+	o.messages <- com.Message{Type: com.StandingsUpdated, Players: standings}
+	await(t, r, Standings)
+
+	o.messages <- com.Message{Type: com.RaceFinished, Player: "bob"}
+	assert.Equal(t, "bob", await(t, r, Finished).Player)
+	_, ok := <-r.Events()
+	assert.False(t, ok)
+}
+
+func TestRaceConnectionLost(t *testing.T) {
+	o := newOpponents()
+	r := NewRace("alice", o, stream("abc", false), channel(make(chan comparison.Character)), time.Second)
+	r.Start()
+	close(o.messages)
+	e := await(t, r, Failed)
+	assert.Equal(t, com.ErrLobbyConnectionLost, e.Err)
+	for range r.Events() {
+	}
+}
+
+func TestRaceStop(t *testing.T) {
+	o := newOpponents()
+	r := NewRace("alice", o, stream("abc", false), channel(make(chan comparison.Character)), time.Second)
+	r.Start()
+	r.Stop()
+	r.Stop()
+	for range r.Events() {
+	}
+}
@@ -0,0 +1,233 @@
+package game
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// RecordingVersion is the current version of the Recording's encoding. It has
+// to be increased, whenever the meaning of encoded fields changes
+const RecordingVersion = 1
+
+// errors
+var (
+	ErrIllegalRecording        = errors.New("the recording is invalid", errors.Warning, errors.Input)
+	ErrUnknownRecordingVersion = errors.New("the recording was created by a newer version", errors.Warning, errors.Input)
+)
+
+// Keystroke is a single attempt-Character of a Recording
+type Keystroke struct {
+	Rune rune
+	// Offset is the time passed between the Recording's first Keystroke and
+	// this one excluding pauses
+	Offset time.Duration
+}
+
+// Recording holds everything needed to replay a Session
+type Recording struct {
+	// Layout is the ID of the keyboard-layout the Session was played with
+	Layout string
+	// Duration is the time the Session ran for excluding pauses
+	Duration time.Duration
+	// Model holds all model-Characters received
+	Model []rune
+	// Keystrokes holds all attempt-Characters, that were compared
+	Keystrokes []Keystroke
+}
+
+// encodedRecording is the compact representation of a Recording. Times are
+// stored in milliseconds and Keystrokes as the string of their runes and the
+// delays between them
+type encodedRecording struct {
+	Version  int     `json:"version"`
+	Layout   string  `json:"layout,omitempty"`
+	Duration int64   `json:"duration"`
+	Model    string  `json:"model"`
+	Keys     string  `json:"keys"`
+	Delays   []int64 `json:"delays"`
+}
+
+// Encode serializes the Recording in a compact format
+func (r *Recording) Encode() string {
+	e := encodedRecording{
+		Version:  RecordingVersion,
+		Layout:   r.Layout,
+		Duration: r.Duration.Milliseconds(),
+		Model:    string(r.Model),
+		Delays:   make([]int64, len(r.Keystrokes)),
+	}
+	keys := make([]rune, len(r.Keystrokes))
+	var last int64
+	for i, k := range r.Keystrokes {
+		keys[i] = k.Rune
+		offset := k.Offset.Milliseconds()
+		e.Delays[i] = offset - last
+		last = offset
+	}
+	e.Keys = string(keys)
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+// DecodeRecording parses a Recording serialized by Encode
+func DecodeRecording(data string) (*Recording, errors.Error) {
+	e := &encodedRecording{}
+	if err := json.Unmarshal([]byte(data), e); err != nil {
+		return nil, ErrIllegalRecording.Append(err.Error())
+	}
+	if e.Version > RecordingVersion {
+		return nil, ErrUnknownRecordingVersion.Append(strconv.Itoa(e.Version))
+	}
+	keys := []rune(e.Keys)
+	if len(keys) != len(e.Delays) {
+		return nil, ErrIllegalRecording.Append("the amount of keys and delays differs")
+	}
+	r := &Recording{
+		Layout:     e.Layout,
+		Duration:   time.Duration(e.Duration) * time.Millisecond,
+		Model:      []rune(e.Model),
+		Keystrokes: make([]Keystroke, len(keys)),
+	}
+	var offset time.Duration
+	for i, k := range keys {
+		if e.Delays[i] < 0 {
+			return nil, ErrIllegalRecording.Append("negative delay")
+		}
+		offset += time.Duration(e.Delays[i]) * time.Millisecond
+		r.Keystrokes[i] = Keystroke{Rune: k, Offset: offset}
+	}
+	return r, nil
+}
+
+// timedCharacter is a comparison.Character, that knows when it was typed
+type timedCharacter struct {
+	comparison.Character
+	time time.Time
+}
+
+func (t timedCharacter) Time() time.Time {
+	return t.time
+}
+
+// Replay plays back a Recording. Its Providers are meant to be used by a
+// Session without an EndCondition, which ends after the last Keystroke. All
+// methods are safe for concurrent use
+type Replay struct {
+	recording *Recording
+	speed     float64
+
+	mutex  sync.Mutex
+	paused bool
+	// changed is closed and replaced whenever the Replay is paused or resumed
+	changed chan bool
+}
+
+// NewReplay creates a Replay of the given Recording, which plays back the
+// Keystrokes speed times as fast as they were recorded
+func NewReplay(r *Recording, speed float64) *Replay {
+	if speed <= 0 {
+		speed = 1
+	}
+	return &Replay{
+		recording: r,
+		speed:     speed,
+		changed:   make(chan bool),
+	}
+}
+
+// Model returns a Provider, whose stream contains the Recording's complete
+// model-text. The stream isn't closed, so the Session doesn't end before the
+// last Keystroke
+func (p *Replay) Model() Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		mod := make(chan comparison.Character, len(p.recording.Model))
+		for _, r := range p.recording.Model {
+			mod <- api.BasicCharacter(r)
+		}
+		return mod
+	}
+}
+
+// Attempt returns a Provider, whose stream plays back the Recording's
+// Keystrokes. The Keystrokes carry the time they would have been typed at in
+// the original speed, so the resulting comparison.Statistics don't depend on
+// the Replay's speed. The stream is closed after the last Keystroke
+func (p *Replay) Attempt() Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		apt := make(chan comparison.Character)
+		go func() {
+			defer close(apt)
+			origin := time.Now()
+			var played time.Duration
+			for _, k := range p.recording.Keystrokes {
+				if !p.wait(time.Duration(float64(k.Offset-played)/p.speed), done) {
+					return
+				}
+				played = k.Offset
+				select {
+				case apt <- timedCharacter{api.BasicCharacter(k.Rune), origin.Add(k.Offset)}:
+				case <-done:
+					return
+				}
+			}
+		}()
+		return apt
+	}
+}
+
+// wait blocks for the given duration not counting the time the Replay is
+// paused. It returns false, if done was closed before
+func (p *Replay) wait(d time.Duration, done <-chan bool) bool {
+	for {
+		p.mutex.Lock()
+		paused, changed := p.paused, p.changed
+		p.mutex.Unlock()
+		if paused {
+			select {
+			case <-changed:
+				continue
+			case <-done:
+				return false
+			}
+		}
+		start := time.Now()
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+			return true
+		case <-changed:
+			t.Stop()
+			d -= time.Since(start)
+		case <-done:
+			t.Stop()
+			return false
+		}
+	}
+}
+
+// Pause stops the playback until Resume is called
+func (p *Replay) Pause() {
+	p.setPaused(true)
+}
+
+// Resume continues a paused playback
+func (p *Replay) Resume() {
+	p.setPaused(false)
+}
+
+func (p *Replay) setPaused(paused bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.paused == paused {
+		return
+	}
+	p.paused = paused
+	close(p.changed)
+	p.changed = make(chan bool)
+}
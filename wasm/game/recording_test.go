@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+)
+
+// fixture is a recorded game, that contains a corrected and an uncorrected
+// miss
+const fixture = `{"version":1,"layout":"us","duration":2000,"model":"the fox","keys":"tge\b\bhe foc","delays":[0,150,120,200,110,130,140,90,160,170,100]}`
+
+// replay plays back the given Recording and returns the last Comparison as
+// well as the Recording of the replaying Session
+func replay(t *testing.T, r *Recording, speed float64) (comparison.Comparison, *Recording) {
+	p := NewReplay(r, speed)
+	s := NewSession(config.EndCondition{}, p.Model(), p.Attempt())
+	s.Start()
+	events := collect(t, s)
+	if len(events[Compared]) == 0 {
+		return nil, s.Recording()
+	}
+	return events[Compared][len(events[Compared])-1].Comparison, s.Recording()
+}
+
+func TestRecordingEncode(t *testing.T) {
+	r := &Recording{
+		Layout:   "de",
+		Duration: 1500 * time.Millisecond,
+		Model:    []rune("äb c"),
+		Keystrokes: []Keystroke{
+			{'ä', 0},
+			{'c', 100*time.Millisecond + 400*time.Microsecond},
+			{'\b', 250 * time.Millisecond},
+			{'b', 300 * time.Millisecond},
+		},
+	}
+	d, err := DecodeRecording(r.Encode())
+	assert.Nil(t, err)
+	r.Keystrokes[1].Offset = 100 * time.Millisecond
+	assert.Equal(t, r, d)
+
+	_, err = DecodeRecording("")
+	assert.Equal(t, ErrIllegalRecording.Type(), err.Type())
+	_, err = DecodeRecording(`{"version":2}`)
+	assert.Equal(t, ErrUnknownRecordingVersion.Type(), err.Type())
+	_, err = DecodeRecording(`{"version":1,"keys":"ab","delays":[0]}`)
+	assert.Equal(t, ErrIllegalRecording.Type(), err.Type())
+	_, err = DecodeRecording(`{"version":1,"keys":"ab","delays":[0,-1]}`)
+	assert.Equal(t, ErrIllegalRecording.Type(), err.Type())
+}
+
+func TestRecordingFixture(t *testing.T) {
+	r, err := DecodeRecording(fixture)
+	assert.Nil(t, err)
+	c, _ := replay(t, r, 100)
+	if !assert.NotNil(t, c) {
+		return
+	}
+	s := c.Statistics()
+	assert.Equal(t, 7, s.TotalCharacters())
+	assert.Equal(t, 6, s.CorrectCharacters())
+	assert.Equal(t, 1, s.CorrectWords())
+	assert.Equal(t, 2, s.TotalMisses())
+	assert.Equal(t, map[rune]int{'h': 1, 'x': 1}, s.Misses())
+	assert.Equal(t, 200*time.Millisecond, s.LongestPause())
+	assert.False(t, c.State().Correct())
+}
+
+func TestRecordSession(t *testing.T) {
+	origin := time.Now()
+	attempt := make(chan comparison.Character, 3)
+	attempt <- timedCharacter{api.BasicCharacter('a'), origin}
+	attempt <- timedCharacter{api.BasicCharacter('x'), origin.Add(120 * time.Millisecond)}
+	attempt <- timedCharacter{api.BasicCharacter('c'), origin.Add(300 * time.Millisecond)}
+	s := NewSession(config.EndCondition{Characters: 3}, stream("abc", false), channel(attempt))
+	s.Start()
+	events := collect(t, s)
+	original := events[Compared][len(events[Compared])-1].Comparison.Statistics()
+
+	r := s.Recording()
+	assert.Equal(t, []rune("abc"), r.Model)
+	assert.Equal(t, []Keystroke{{'a', 0}, {'x', 120 * time.Millisecond}, {'c', 300 * time.Millisecond}}, r.Keystrokes)
+
+	d, err := DecodeRecording(r.Encode())
+	assert.Nil(t, err)
+	c, replayed := replay(t, d, 10)
+	assert.Equal(t, original.TotalCharacters(), c.Statistics().TotalCharacters())
+	assert.Equal(t, original.TotalMisses(), c.Statistics().TotalMisses())
+	assert.Equal(t, original.MeanLatency(), c.Statistics().MeanLatency())
+	assert.Equal(t, r.Keystrokes, replayed.Keystrokes)
+}
+
+func TestReplayPause(t *testing.T) {
+	r := &Recording{
+		Model:      []rune("ab"),
+		Keystrokes: []Keystroke{{'a', 0}, {'b', 50 * time.Millisecond}},
+	}
+	p := NewReplay(r, 1)
+	s := NewSession(config.EndCondition{}, p.Model(), p.Attempt())
+	s.Start()
+	for e := range s.Events() {
+		if e.Type == Compared {
+			break
+		}
+	}
+	p.Pause()
+	timeout := time.After(150 * time.Millisecond)
+wait:
+	for {
+		select {
+		case e := <-s.Events():
+			assert.NotEqual(t, Compared, e.Type, "a keystroke was replayed during the pause")
+		case <-timeout:
+			break wait
+		}
+	}
+	p.Resume()
+	events := collect(t, s)
+	assert.Equal(t, 1, len(events[Compared]))
+}
@@ -0,0 +1,377 @@
+// Package game runs typing-games. A Session compares a model-stream with the
+// user's attempt and reports the results as a stream of Events
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// errors
+var (
+	ErrAlreadyRunning             = errors.New("the game is already running", errors.Critical)
+	ErrUnknownPanicCause          = errors.New("something unexpected happened", errors.Critical)
+	ErrBackendCommunicationFailed = errors.New("a critical error occurred while communicating with the backend", errors.Critical, errors.Server)
+)
+
+// Provider opens a Character-stream for a Session. It is called once, when the
+// Session is started. The Provider must release all of its resources, as soon
+// as done is closed. Providers may panic with an errors.Error, which is
+// reported as a Failed Event
+type Provider func(done <-chan bool) <-chan comparison.Character
+
+// EventType identifies the kind of an Event
+type EventType int
+
+// event types
+const (
+	// ModelCharacter Events carry a Character received from the model-stream
+	ModelCharacter EventType = iota
+	// Compared Events carry the Comparison caused by an attempt-Character
+	Compared
+	// Failed Events carry an error, that occurred while running the Session
+	Failed
+	// Standings Events carry the Players of a Race ordered by their progress
+	Standings
+	// Eliminated Events carry the name of the Player eliminated from a Race
+	Eliminated
+	// Finished Events carry the name of a Race's winner
+	Finished
+)
+
+// Event is emitted by a Session. Only the field matching the Event's Type is
+// set
+type Event struct {
+	Type       EventType
+	Character  comparison.Character
+	Comparison comparison.Comparison
+	Err        errors.Error
+	Players    []com.Player
+	Player     string
+}
+
+// Session is a single game. A Session is started once and runs until its
+// config.EndCondition is met, until its model-stream ends, until it fails, or
+// until it is stopped. All methods are safe for concurrent use
+type Session struct {
+	end     config.EndCondition
+	model   Provider
+	attempt Provider
+	events  chan Event
+
+	mutex   sync.Mutex
+	started bool
+	stopped bool
+	paused  bool
+	done    chan bool
+	// timer stops the Session after the end's Duration. It is created on the
+	// first Comparison. remaining is the Duration left, when the timer was
+	// stopped by Pause
+	timer     *time.Timer
+	deadline  time.Time
+	remaining time.Duration
+	// start is the time of the first keystroke. pausedAt is the time, the
+	// current pause or the Session itself began to stop the clock, and
+	// pausedFor sums up all finished pauses after start
+	start     time.Time
+	pausedAt  time.Time
+	pausedFor time.Duration
+	// recording holds all Characters forwarded so far. origin is the time of
+	// the first recorded Keystroke
+	recording Recording
+	origin    time.Time
+}
+
+// NewSession creates a Session, which ends according to the given
+// config.EndCondition and compares the stream opened by model with the one
+// opened by attempt
+func NewSession(end config.EndCondition, model, attempt Provider) *Session {
+	return &Session{
+		end:     end,
+		model:   model,
+		attempt: attempt,
+		events:  make(chan Event),
+		done:    make(chan bool),
+	}
+}
+
+// Events returns the stream of the Session's Events. It is closed after the
+// Session has stopped and all of its resources are released. The stream must
+// be consumed until it is closed
+func (s *Session) Events() <-chan Event {
+	return s.events
+}
+
+// Start runs the Session asynchronously. If the Session was started before,
+// ErrAlreadyRunning is returned
+func (s *Session) Start() errors.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.started {
+		return ErrAlreadyRunning
+	}
+	s.started = true
+	go s.run()
+	return nil
+}
+
+// Stop ends the Session. Stopping a stopped Session has no effect
+func (s *Session) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	if !s.paused {
+		s.pausedAt = time.Now()
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	close(s.done)
+}
+
+// Pause discards all attempt-Characters and freezes the end's Duration as well
+// as Elapsed until Resume is called
+func (s *Session) Pause() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.paused || s.stopped {
+		return
+	}
+	s.paused = true
+	s.pausedAt = time.Now()
+	if s.timer != nil && s.timer.Stop() {
+		s.remaining = time.Until(s.deadline)
+	}
+}
+
+// Resume continues a paused Session
+func (s *Session) Resume() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.paused || s.stopped {
+		return
+	}
+	s.paused = false
+	if !s.start.IsZero() {
+		// the first keystroke may have been compared after the pause began
+		from := s.pausedAt
+		if s.start.After(from) {
+			from = s.start
+		}
+		s.pausedFor += time.Since(from)
+	}
+	if s.timer != nil {
+		s.startTimer(s.remaining)
+	}
+}
+
+// Paused returns whether the Session is paused
+func (s *Session) Paused() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.paused
+}
+
+// Elapsed returns the time passed since the first keystroke excluding all
+// pauses. The time stops, when the Session is paused or stopped
+func (s *Session) Elapsed() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.start.IsZero() {
+		return 0
+	}
+	now := time.Now()
+	if s.paused || s.stopped {
+		now = s.pausedAt
+	}
+	if elapsed := now.Sub(s.start) - s.pausedFor; elapsed > 0 {
+		return elapsed
+	}
+	return 0
+}
+
+// Recording returns a Recording of all model-Characters received and all
+// attempt-Characters compared so far. Its Layout is left empty
+func (s *Session) Recording() *Recording {
+	elapsed := s.Elapsed()
+	if s.end.Duration > 0 && elapsed > s.end.Duration {
+		elapsed = s.end.Duration
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return &Recording{
+		Duration:   elapsed,
+		Model:      append([]rune(nil), s.recording.Model...),
+		Keystrokes: append([]Keystroke(nil), s.recording.Keystrokes...),
+	}
+}
+
+// startTimer stops the Session after d. If the Session is paused, the timer
+// is started by Resume. The caller must hold the mutex
+func (s *Session) startTimer(d time.Duration) {
+	s.remaining = d
+	s.deadline = time.Now().Add(d)
+	s.timer = time.AfterFunc(d, s.Stop)
+	if s.paused {
+		s.timer.Stop()
+	}
+}
+
+func (s *Session) run() {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(s.events)
+	}()
+	defer s.recoverPanics()
+
+	model := s.model(s.done)
+	attempt := s.attempt(s.done)
+	mCopy := make(chan comparison.Character, cap(model))
+	aCopy := make(chan comparison.Character, cap(attempt))
+	cmp := make(chan comparison.Comparison)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		defer s.recoverPanics()
+		s.forwardModel(model, mCopy)
+	}()
+	go func() {
+		defer wg.Done()
+		defer s.recoverPanics()
+		s.forwardAttempt(attempt, aCopy)
+	}()
+	go func() {
+		defer wg.Done()
+		defer s.recoverPanics()
+		s.handleComparisons(cmp)
+	}()
+	comparison.Compare(mCopy, aCopy, cmp)
+}
+
+// forwardModel pipes the model-stream into dst and emits ModelCharacter
+// Events. The Session is stopped, when the model-stream ends
+func (s *Session) forwardModel(model <-chan comparison.Character, dst chan<- comparison.Character) {
+	defer close(dst)
+	for {
+		select {
+		case c, ok := <-model:
+			if !ok {
+				s.Stop()
+				return
+			}
+			select {
+			case dst <- c:
+			case <-s.done:
+				return
+			}
+			s.mutex.Lock()
+			s.recording.Model = append(s.recording.Model, c.Rune())
+			s.mutex.Unlock()
+			s.events <- Event{Type: ModelCharacter, Character: c}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// forwardAttempt pipes the attempt-stream into dst, unless the Session is
+// paused. The Characters are timed by the Session's clock, which stops during
+// pauses, unless they are comparison.Timed already
+func (s *Session) forwardAttempt(attempt <-chan comparison.Character, dst chan<- comparison.Character) {
+	defer close(dst)
+	for {
+		select {
+		case c, ok := <-attempt:
+			if !ok {
+				return
+			}
+			if s.Paused() {
+				continue
+			}
+			s.mutex.Lock()
+			now := time.Now().Add(-s.pausedFor)
+			s.mutex.Unlock()
+			if t, ok := c.(comparison.Timed); ok {
+				now = t.Time()
+			}
+			select {
+			case dst <- timedCharacter{c, now}:
+			case <-s.done:
+				return
+			}
+			s.mutex.Lock()
+			if s.origin.IsZero() {
+				s.origin = now
+			}
+			s.recording.Keystrokes = append(s.recording.Keystrokes, Keystroke{Rune: c.Rune(), Offset: now.Sub(s.origin)})
+			s.mutex.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// handleComparisons emits Compared Events and stops the Session, as soon as
+// the end is reached or the comparison is over. Comparisons received after the Session has stopped are
+// discarded
+func (s *Session) handleComparisons(cmp <-chan comparison.Comparison) {
+	started := false
+	for {
+		select {
+		case c, ok := <-cmp:
+			if !ok {
+				s.Stop()
+				return
+			}
+			select {
+			case <-s.done:
+				continue
+			default:
+			}
+			if !started {
+				started = true
+				s.mutex.Lock()
+				s.start = c.Statistics().Start()
+				if s.end.Duration > 0 && !s.stopped {
+					s.startTimer(s.end.Duration)
+				}
+				s.mutex.Unlock()
+			}
+			s.events <- Event{Type: Compared, Comparison: c}
+			if s.end.Reached(c.Statistics()) {
+				s.Stop()
+			}
+		case <-s.done:
+			go func() {
+				for range cmp {
+				}
+			}()
+			return
+		}
+	}
+}
+
+// recoverPanics reports a panic as a Failed Event and stops the Session
+func (s *Session) recoverPanics() {
+	e := recover()
+	if e == nil {
+		return
+	}
+	err, ok := e.(errors.Error)
+	if !ok {
+		err = ErrUnknownPanicCause.Append(fmt.Sprint(e))
+	}
+	s.Stop()
+	s.events <- Event{Type: Failed, Err: err}
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// stream returns a Provider, which opens a stream containing text. The stream
+// is closed afterwards, if closed is true
+func stream(text string, closed bool) Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		c := make(chan comparison.Character, len(text))
+		for _, r := range text {
+			c <- api.BasicCharacter(r)
+		}
+		if closed {
+			close(c)
+		}
+		return c
+	}
+}
+
+// channel returns a Provider, which opens the given channel
+func channel(c chan comparison.Character) Provider {
+	return func(done <-chan bool) <-chan comparison.Character {
+		return c
+	}
+}
+
+// collect reads all Events of s and fails, if the stream isn't closed within
+// a second
+func collect(t *testing.T, s *Session) map[EventType][]Event {
+	events := make(map[EventType][]Event)
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				return events
+			}
+			events[e.Type] = append(events[e.Type], e)
+		case <-timeout:
+			t.Fatal("the session didn't stop")
+			return events
+		}
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	s := NewSession(config.EndCondition{Characters: 2}, stream("abc", false), stream("axc", false))
+	assert.Nil(t, s.Start())
+	events := collect(t, s)
+	assert.Equal(t, 2, len(events[Compared]))
+	assert.True(t, len(events[ModelCharacter]) >= 2)
+	assert.Empty(t, events[Failed])
+	last := events[Compared][1].Comparison
+	assert.Equal(t, 2, last.Statistics().TotalCharacters())
+	assert.Equal(t, 1, last.Statistics().TotalMisses())
+	assert.Equal(t, ErrAlreadyRunning, s.Start())
+}
+
+func TestSessionModelEnd(t *testing.T) {
+	s := NewSession(config.EndCondition{}, stream("ab", true), channel(make(chan comparison.Character)))
+	s.Start()
+	events := collect(t, s)
+	assert.Equal(t, 2, len(events[ModelCharacter]))
+	assert.Empty(t, events[Compared])
+}
+
+func TestSessionStop(t *testing.T) {
+	s := NewSession(config.EndCondition{}, stream("ab", false), channel(make(chan comparison.Character)))
+	s.Start()
+	s.Stop()
+	collect(t, s)
+	// stopping twice has no effect
+	s.Stop()
+}
+
+func TestSessionFailure(t *testing.T) {
+	err := errors.New("test", errors.Critical)
+	s := NewSession(config.EndCondition{}, func(done <-chan bool) <-chan comparison.Character {
+		panic(err)
+	}, stream("", false))
+	s.Start()
+	events := collect(t, s)
+	assert.Equal(t, 1, len(events[Failed]))
+	assert.Equal(t, err, events[Failed][0].Err)
+}
+
+func TestSessionPause(t *testing.T) {
+	attempt := make(chan comparison.Character)
+	s := NewSession(config.EndCondition{}, stream("abc", false), channel(attempt))
+	s.Start()
+	go func() {
+		s.Pause()
+		assert.True(t, s.Paused())
+		// once the third Character is received, the first two were discarded
+		attempt <- api.BasicCharacter('x')
+		attempt <- api.BasicCharacter('y')
+		attempt <- api.BasicCharacter('z')
+		s.Resume()
+		assert.False(t, s.Paused())
+		attempt <- api.BasicCharacter('a')
+	}()
+	var typed []rune
+	for e := range s.Events() {
+		if e.Type == Compared {
+			r := e.Comparison.Changes()[0].Rune()
+			typed = append(typed, r)
+			if r == 'a' {
+				s.Stop()
+			}
+		}
+	}
+	if assert.NotEmpty(t, typed) {
+		// only 'z' may have passed during the race with Resume
+		for _, r := range typed[:len(typed)-1] {
+			assert.Equal(t, 'z', r)
+		}
+		assert.Equal(t, 'a', typed[len(typed)-1])
+	}
+}
+
+func TestSessionPauseDuration(t *testing.T) {
+	attempt := make(chan comparison.Character, 1)
+	s := NewSession(config.EndCondition{Duration: 50 * time.Millisecond}, stream("abc", false), channel(attempt))
+	s.Start()
+	attempt <- api.BasicCharacter('a')
+	for e := range s.Events() {
+		if e.Type == Compared {
+			break
+		}
+	}
+	s.Pause()
+	elapsed := s.Elapsed()
+	assert.True(t, elapsed > 0)
+	timeout := time.After(150 * time.Millisecond)
+wait:
+	for {
+		select {
+		case _, ok := <-s.Events():
+			if !ok {
+				t.Fatal("the session stopped while it was paused")
+			}
+		case <-timeout:
+			break wait
+		}
+	}
+	assert.Equal(t, elapsed, s.Elapsed())
+	s.Resume()
+	collect(t, s)
+	// the pause isn't part of the elapsed time
+	assert.True(t, s.Elapsed() >= 50*time.Millisecond)
+	assert.True(t, s.Elapsed() < 150*time.Millisecond)
+}
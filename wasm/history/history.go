@@ -0,0 +1,239 @@
+// Package history records the results of finished games, so the user's
+// progress can be tracked across sessions
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// Version is the current version of the stored history's schema. It has to be
+// increased, whenever the meaning of stored fields changes
+const Version = 1
+
+// StorageKey is the key the history is stored under
+const StorageKey = "notypo.history"
+
+// MaxRecords is the maximum amount of Records kept. If it is exceeded, the
+// oldest Records are discarded
+var MaxRecords = 1000
+
+// errors
+var (
+	ErrIllegalHistory        = errors.New("the stored history is invalid", errors.Warning, errors.Input)
+	ErrUnknownHistoryVersion = errors.New("the stored history was created by a newer version", errors.Warning, errors.Input)
+)
+
+// Storage is a key-value-store, e.g. the browser's localStorage
+type Storage interface {
+	GetItem(key string) (string, bool)
+	SetItem(key, value string)
+	RemoveItem(key string)
+}
+
+// Record holds the results of a single game
+type Record struct {
+	Time     time.Time            `json:"time"`
+	Type     api.StreamSourceType `json:"type"`
+	Charset  string               `json:"charset"`
+	Duration time.Duration        `json:"duration"`
+
+	TotalCharacters   int `json:"totalCharacters"`
+	CorrectCharacters int `json:"correctCharacters"`
+	CorrectWords      int `json:"correctWords"`
+	TotalMisses       int `json:"totalMisses"`
+	TotalStrokes      int `json:"totalStrokes"`
+
+	NetWPM   float64 `json:"netWPM"`
+	GrossWPM float64 `json:"grossWPM"`
+	CPM      float64 `json:"cpm"`
+	Accuracy float64 `json:"accuracy"`
+
+	// Misses holds the amount of misses per model-character
+	Misses map[string]int `json:"misses,omitempty"`
+	// Keystrokes holds the analysis.Stats per model-character
+	Keystrokes map[string]analysis.Stats `json:"keystrokes,omitempty"`
+}
+
+// NewRecord creates a Record of a game played at the given time using the
+// given description, which ended with the given Statistics and Analysis after
+// the given duration
+func NewRecord(at time.Time, d *config.Description, s comparison.Statistics, a *analysis.Analysis, duration time.Duration) Record {
+	charset := make([]rune, len(d.Charset))
+	for i, c := range d.Charset {
+		charset[i] = c.Rune()
+	}
+	sort.Slice(charset, func(i, j int) bool {
+		return charset[i] < charset[j]
+	})
+	r := comparison.RatesFor(s, duration)
+	misses := make(map[string]int)
+	for c, n := range s.Misses() {
+		misses[string(c)] = n
+	}
+	keystrokes := make(map[string]analysis.Stats)
+	for c, k := range a.Characters() {
+		keystrokes[string(c)] = k
+	}
+	return Record{
+		Time:              at,
+		Type:              d.Type,
+		Charset:           string(charset),
+		Duration:          duration,
+		TotalCharacters:   s.TotalCharacters(),
+		CorrectCharacters: s.CorrectCharacters(),
+		CorrectWords:      s.CorrectWords(),
+		TotalMisses:       s.TotalMisses(),
+		TotalStrokes:      s.TotalStrokes(),
+		NetWPM:            r.NetWPM(),
+		GrossWPM:          r.GrossWPM(),
+		CPM:               r.CPM(),
+		Accuracy:          r.Accuracy(),
+		Misses:            misses,
+		Keystrokes:        keystrokes,
+	}
+}
+
+// Configuration identifies the Record's game-configuration. Only Records of
+// the same Configuration are comparable
+func (r Record) Configuration() string {
+	return string(r.Type) + ":" + r.Charset
+}
+
+// Day summarizes all Records of a single day
+type Day struct {
+	// Date is the day's midnight in local time
+	Date     time.Time
+	Games    int
+	NetWPM   float64
+	Accuracy float64
+}
+
+// History is a persisted list of Records
+type History struct {
+	storage Storage
+}
+
+type stored struct {
+	Version int      `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// New creates a History persisted in the given Storage
+func New(s Storage) *History {
+	return &History{
+		storage: s,
+	}
+}
+
+// Records returns all Records ordered by time
+func (h *History) Records() ([]Record, errors.Error) {
+	data, ok := h.storage.GetItem(StorageKey)
+	if !ok {
+		return []Record{}, nil
+	}
+	s := &stored{}
+	err := json.Unmarshal([]byte(data), s)
+	if err != nil {
+		return nil, ErrIllegalHistory.Append(err.Error())
+	}
+	if s.Version > Version {
+		return nil, ErrUnknownHistoryVersion.Append(strconv.Itoa(s.Version))
+	}
+	if s.Version <= 0 {
+		return nil, ErrIllegalHistory.Append("missing version")
+	}
+	sort.SliceStable(s.Records, func(i, j int) bool {
+		return s.Records[i].Time.Before(s.Records[j].Time)
+	})
+	return s.Records, nil
+}
+
+// Add appends the given Record. An invalid stored history is replaced
+func (h *History) Add(r Record) errors.Error {
+	records, err := h.Records()
+	if err != nil && err.Type() != ErrIllegalHistory.Type() {
+		return err
+	}
+	records = append(records, r)
+	if len(records) > MaxRecords {
+		records = records[len(records)-MaxRecords:]
+	}
+	data, _ := json.Marshal(&stored{
+		Version: Version,
+		Records: records,
+	})
+	h.storage.SetItem(StorageKey, string(data))
+	return nil
+}
+
+// Clear removes all Records
+func (h *History) Clear() {
+	h.storage.RemoveItem(StorageKey)
+}
+
+// PersonalBests returns the Record with the highest NetWPM per Configuration
+func PersonalBests(records []Record) map[string]Record {
+	bests := make(map[string]Record)
+	for _, r := range records {
+		if b, ok := bests[r.Configuration()]; !ok || r.NetWPM > b.NetWPM {
+			bests[r.Configuration()] = r
+		}
+	}
+	return bests
+}
+
+// Keystrokes sums up the Keystrokes of the latest n of the given Records, which
+// are of the given type
+func Keystrokes(records []Record, t api.StreamSourceType, n int) map[rune]analysis.Stats {
+	keystrokes := make(map[rune]analysis.Stats)
+	for i := len(records) - 1; i >= 0 && n > 0; i-- {
+		if records[i].Type != t {
+			continue
+		}
+		n--
+		for c, k := range records[i].Keystrokes {
+			r := []rune(c)
+			if len(r) == 1 {
+				keystrokes[r[0]] = keystrokes[r[0]].Add(k)
+			}
+		}
+	}
+	return keystrokes
+}
+
+// Days groups the given Records by their local date and averages their NetWPM
+// and Accuracy. The result is ordered by date
+func Days(records []Record) []Day {
+	days := make([]Day, 0)
+	index := make(map[time.Time]int)
+	for _, r := range records {
+		y, m, d := r.Time.Local().Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		i, ok := index[date]
+		if !ok {
+			i = len(days)
+			index[date] = i
+			days = append(days, Day{Date: date})
+		}
+		days[i].Games++
+		days[i].NetWPM += r.NetWPM
+		days[i].Accuracy += r.Accuracy
+	}
+	for i := range days {
+		days[i].NetWPM /= float64(days[i].Games)
+		days[i].Accuracy /= float64(days[i].Games)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days
+}
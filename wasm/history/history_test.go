@@ -0,0 +1,177 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+)
+
+type mapStorage map[string]string
+
+func (m mapStorage) GetItem(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m mapStorage) SetItem(key, value string) {
+	m[key] = value
+}
+
+func (m mapStorage) RemoveItem(key string) {
+	delete(m, key)
+}
+
+// statistics returns the final Statistics and Analysis of typing attempt on
+// model
+func statistics(model, attempt string) (comparison.Statistics, *analysis.Analysis) {
+	m := make(chan comparison.Character, len(model))
+	for _, r := range model {
+		m <- api.BasicCharacter(r)
+	}
+	a := make(chan comparison.Character, len(attempt))
+	for _, r := range attempt {
+		a <- api.BasicCharacter(r)
+	}
+	close(a)
+	cmp := make(chan comparison.Comparison)
+	go comparison.Compare(m, a, cmp)
+	var s comparison.Statistics
+	an := analysis.New()
+	for c := range cmp {
+		s = c.Statistics()
+		for _, m := range c.Changes() {
+			an.Add(m)
+		}
+	}
+	return s, an
+}
+
+func description(t api.StreamSourceType, charset string) *config.Description {
+	d := &config.Description{}
+	d.Type = t
+	for _, r := range charset {
+		d.Charset = append(d.Charset, api.BasicCharacter(r))
+	}
+	return d
+}
+
+func TestNewRecord(t *testing.T) {
+	at := time.Date(2019, 5, 1, 12, 0, 0, 0, time.Local)
+	s, a := statistics("as as", "ax as")
+	r := NewRecord(at, description(api.Random, "sa "), s, a, 6*time.Second)
+	assert.Equal(t, at, r.Time)
+	assert.Equal(t, " as", r.Charset)
+	assert.Equal(t, "Random: as", r.Configuration())
+	assert.Equal(t, 5, r.TotalCharacters)
+	assert.Equal(t, 1, r.TotalMisses)
+	assert.Equal(t, map[string]int{"s": 1}, r.Misses)
+	assert.Equal(t, 2, r.Keystrokes["s"].Strokes)
+	assert.Equal(t, 1, r.Keystrokes["s"].Misses)
+	assert.InDelta(t, 10, r.GrossWPM, 0.001)
+	assert.InDelta(t, 0.8, r.Accuracy, 0.001)
+}
+
+func TestHistory(t *testing.T) {
+	s := mapStorage{}
+	h := New(s)
+	records, err := h.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	day := time.Date(2019, 5, 1, 12, 0, 0, 0, time.Local)
+	h.Add(Record{Time: day.Add(time.Hour), Type: api.Random, Charset: "as", NetWPM: 20})
+	h.Add(Record{Time: day, Type: api.Random, Charset: "as", NetWPM: 30})
+	records, err = h.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, 30.0, records[0].NetWPM)
+
+	// the history survives a reload
+	records, _ = New(s).Records()
+	assert.Equal(t, 2, len(records))
+
+	h.Clear()
+	records, _ = h.Records()
+	assert.Empty(t, records)
+}
+
+func TestHistoryLimit(t *testing.T) {
+	defer func(max int) {
+		MaxRecords = max
+	}(MaxRecords)
+	MaxRecords = 3
+	h := New(mapStorage{})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		h.Add(Record{Time: start.Add(time.Duration(i) * time.Minute), NetWPM: float64(i)})
+	}
+	records, _ := h.Records()
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, 2.0, records[0].NetWPM)
+}
+
+func TestInvalidHistory(t *testing.T) {
+	s := mapStorage{StorageKey: "{"}
+	h := New(s)
+	_, err := h.Records()
+	assert.Equal(t, ErrIllegalHistory.Type(), err.Type())
+	// an invalid history is replaced
+	assert.Nil(t, h.Add(Record{NetWPM: 1}))
+	records, err := h.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+
+	s[StorageKey] = `{"version":999999,"records":[]}`
+	_, err = h.Records()
+	assert.Equal(t, ErrUnknownHistoryVersion.Type(), err.Type())
+	// a newer history is never overwritten
+	assert.NotNil(t, h.Add(Record{NetWPM: 1}))
+}
+
+func TestPersonalBests(t *testing.T) {
+	bests := PersonalBests([]Record{
+		{Type: api.Random, Charset: "as", NetWPM: 20},
+		{Type: api.Random, Charset: "as", NetWPM: 30},
+		{Type: api.Random, Charset: "asd", NetWPM: 10},
+		{Type: api.Dictionary, Charset: "as", NetWPM: 40},
+	})
+	assert.Equal(t, 3, len(bests))
+	assert.Equal(t, 30.0, bests["Random:as"].NetWPM)
+	assert.Equal(t, 10.0, bests["Random:asd"].NetWPM)
+	assert.Equal(t, 40.0, bests["Dictionary:as"].NetWPM)
+}
+
+func TestKeystrokes(t *testing.T) {
+	records := []Record{
+		{Type: api.Random, Keystrokes: map[string]analysis.Stats{"a": {Strokes: 100, Misses: 100}}},
+		{Type: api.Random, Keystrokes: map[string]analysis.Stats{"a": {Strokes: 2, Misses: 1}}},
+		{Type: api.Dictionary, Keystrokes: map[string]analysis.Stats{"a": {Strokes: 7}}},
+		{Type: api.Random, Keystrokes: map[string]analysis.Stats{"a": {Strokes: 3}, "b": {Strokes: 1, Misses: 1}}},
+	}
+	k := Keystrokes(records, api.Random, 2)
+	assert.Equal(t, analysis.Stats{Strokes: 5, Misses: 1}, k['a'])
+	assert.Equal(t, analysis.Stats{Strokes: 1, Misses: 1}, k['b'])
+	assert.Empty(t, Keystrokes(records, api.Random, 0))
+}
+
+func TestDays(t *testing.T) {
+	first := time.Date(2019, 5, 1, 9, 0, 0, 0, time.Local)
+	second := time.Date(2019, 5, 3, 20, 0, 0, 0, time.Local)
+	days := Days([]Record{
+		{Time: second, NetWPM: 40, Accuracy: 1},
+		{Time: first, NetWPM: 20, Accuracy: 0.9},
+		{Time: first.Add(time.Hour), NetWPM: 30, Accuracy: 0.8},
+	})
+	assert.Equal(t, 2, len(days))
+	assert.Equal(t, time.Date(2019, 5, 1, 0, 0, 0, 0, time.Local), days[0].Date)
+	assert.Equal(t, 2, days[0].Games)
+	assert.InDelta(t, 25, days[0].NetWPM, 0.001)
+	assert.InDelta(t, 0.85, days[0].Accuracy, 0.001)
+	assert.Equal(t, 1, days[1].Games)
+	assert.InDelta(t, 40, days[1].NetWPM, 0.001)
+}
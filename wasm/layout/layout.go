@@ -0,0 +1,263 @@
+// Package layout describes physical keyboard layouts: the characters produced
+// by each key on each level, the keys' positions and the fingers, which type
+// them
+package layout
+
+import (
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// Finger identifies a finger used for typing
+type Finger int
+
+// fingers from left to right
+const (
+	LeftLittle Finger = iota
+	LeftRing
+	LeftMiddle
+	LeftIndex
+	Thumbs
+	RightIndex
+	RightMiddle
+	RightRing
+	RightLittle
+)
+
+func (f Finger) String() string {
+	switch f {
+	case LeftLittle:
+		return "left little finger"
+	case LeftRing:
+		return "left ring finger"
+	case LeftMiddle:
+		return "left middle finger"
+	case LeftIndex:
+		return "left index finger"
+	case Thumbs:
+		return "thumbs"
+	case RightIndex:
+		return "right index finger"
+	case RightMiddle:
+		return "right middle finger"
+	case RightRing:
+		return "right ring finger"
+	case RightLittle:
+		return "right little finger"
+	default:
+		return "unknown finger"
+	}
+}
+
+// Level is a modifier-state of the keyboard, which determines the character a
+// key produces
+type Level int
+
+// levels
+const (
+	// Base is the level without any modifier
+	Base Level = iota
+	// Shift is the level reached by holding shift
+	Shift
+	// AltGr is the level reached by holding AltGr
+	AltGr
+	levels
+)
+
+// rows
+const (
+	NumberRow = iota
+	TopRow
+	HomeRow
+	BottomRow
+	SpaceRow
+)
+
+// Key is a single key of a Layout
+type Key struct {
+	Row    int
+	Column int
+	Finger Finger
+	// Levels holds the rune the key produces on each Level. It is zero, if
+	// the key produces nothing on that level
+	Levels [levels]rune
+}
+
+// Rune returns the rune the Key produces on its Base level
+func (k Key) Rune() rune {
+	return k.Levels[Base]
+}
+
+// Layout describes the character-keys of a physical keyboard row by row
+type Layout struct {
+	// ID identifies the Layout. It is used for persisting the user's choice
+	// and must therefore never change
+	ID   string
+	Name string
+	Rows [][]Key
+}
+
+// Key returns the Key, which produces the given rune, and the Level it is
+// produced on
+func (l *Layout) Key(r rune) (Key, Level, bool) {
+	for level := Base; level < levels; level++ {
+		for _, row := range l.Rows {
+			for _, k := range row {
+				if k.Levels[level] == r {
+					return k, level, true
+				}
+			}
+		}
+	}
+	return Key{}, 0, false
+}
+
+// Finger returns the Finger, which types the given rune
+func (l *Layout) Finger(r rune) (Finger, bool) {
+	k, _, ok := l.Key(r)
+	return k.Finger, ok
+}
+
+// Characters returns the runes produced on the given Level by all Keys, which
+// are accepted by filter
+func (l *Layout) Characters(level Level, filter func(Key) bool) []rune {
+	runes := make([]rune, 0)
+	for _, row := range l.Rows {
+		for _, k := range row {
+			if k.Levels[level] != 0 && filter(k) {
+				runes = append(runes, k.Levels[level])
+			}
+		}
+	}
+	return runes
+}
+
+// Letters returns whether the Key produces a letter on its Base level
+func Letters(k Key) bool {
+	return unicode.IsLetter(k.Rune())
+}
+
+// layouts
+var (
+	US = &Layout{ID: "us", Name: "English (US)", Rows: [][]Key{
+		row(NumberRow, ansiNumberFingers, "`1234567890-=", "~!@#$%^&*()_+", ""),
+		row(TopRow, ansiTopFingers, "qwertyuiop[]\\", "QWERTYUIOP{}|", ""),
+		row(HomeRow, ansiHomeFingers, "asdfghjkl;'", "ASDFGHJKL:\"", ""),
+		row(BottomRow, ansiBottomFingers, "zxcvbnm,./", "ZXCVBNM<>?", ""),
+		space(),
+	}}
+	UK = &Layout{ID: "uk", Name: "English (UK)", Rows: [][]Key{
+		row(NumberRow, isoNumberFingers, "`1234567890-=", "¬!\"£$%^&*()_+", "¦   €        "),
+		row(TopRow, isoTopFingers, "qwertyuiop[]", "QWERTYUIOP{}", "  é   úíó   "),
+		row(HomeRow, isoHomeFingers, "asdfghjkl;'#", "ASDFGHJKL:@~", "á           "),
+		row(BottomRow, isoBottomFingers, "\\zxcvbnm,./", "|ZXCVBNM<>?", ""),
+		space(),
+	}}
+	German = &Layout{ID: "de", Name: "German (QWERTZ)", Rows: [][]Key{
+		row(NumberRow, isoNumberFingers, "^1234567890ß´", "°!\"§$%&/()=?`", "  ²³   {[]}\\ "),
+		row(TopRow, isoTopFingers, "qwertzuiopü+", "QWERTZUIOPÜ*", "@ €        ~"),
+		row(HomeRow, isoHomeFingers, "asdfghjklöä#", "ASDFGHJKLÖÄ'", ""),
+		row(BottomRow, isoBottomFingers, "<yxcvbnm,.-", ">YXCVBNM;:_", "|      µ   "),
+		space(),
+	}}
+	French = &Layout{ID: "fr", Name: "French (AZERTY)", Rows: [][]Key{
+		row(NumberRow, isoNumberFingers, "²&é\"'(-è_çà)=", " 1234567890°+", "  ~#{[|`\\^@]}"),
+		row(TopRow, isoTopFingers, "azertyuiop^$", "AZERTYUIOP¨£", "  €        ¤"),
+		row(HomeRow, isoHomeFingers, "qsdfghjklmù*", "QSDFGHJKLM%µ", ""),
+		row(BottomRow, isoBottomFingers, "<wxcvbn,;:!", ">WXCVBN?./§", ""),
+		space(),
+	}}
+	Dvorak = &Layout{ID: "dvorak", Name: "Dvorak", Rows: [][]Key{
+		row(NumberRow, ansiNumberFingers, "`1234567890[]", "~!@#$%^&*(){}", ""),
+		row(TopRow, ansiTopFingers, "',.pyfgcrl/=\\", "\"<>PYFGCRL?+|", ""),
+		row(HomeRow, ansiHomeFingers, "aoeuidhtns-", "AOEUIDHTNS_", ""),
+		row(BottomRow, ansiBottomFingers, ";qjkxbmwvz", ":QJKXBMWVZ", ""),
+		space(),
+	}}
+	Colemak = &Layout{ID: "colemak", Name: "Colemak", Rows: [][]Key{
+		row(NumberRow, ansiNumberFingers, "`1234567890-=", "~!@#$%^&*()_+", ""),
+		row(TopRow, ansiTopFingers, "qwfpgjluy;[]\\", "QWFPGJLUY:{}|", ""),
+		row(HomeRow, ansiHomeFingers, "arstdhneio'", "ARSTDHNEIO\"", ""),
+		row(BottomRow, ansiBottomFingers, "zxcvbkm,./", "ZXCVBKM<>?", ""),
+		space(),
+	}}
+)
+
+// All returns all known Layouts
+func All() []*Layout {
+	return []*Layout{US, UK, German, French, Dvorak, Colemak}
+}
+
+// ByID returns the Layout with the given ID
+func ByID(id string) (*Layout, bool) {
+	for _, l := range All() {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// Default returns the Layout commonly used with the given language
+func Default(l language.Tag) *Layout {
+	base, _ := l.Base()
+	region, _ := l.Region()
+	switch base.String() {
+	case "de":
+		return German
+	case "fr":
+		return French
+	case "en":
+		if region.String() == "GB" {
+			return UK
+		}
+	}
+	return US
+}
+
+// finger-assignments of the physical rows. ANSI-keyboards have the backslash
+// in the top row, ISO-keyboards have an additional key in the home row and
+// left of the bottom row
+const (
+	ansiNumberFingers = "0012335567888"
+	ansiTopFingers    = "0123355678888"
+	ansiHomeFingers   = "01233556788"
+	ansiBottomFingers = "0123355678"
+	isoNumberFingers  = "0012335567888"
+	isoTopFingers     = "012335567888"
+	isoHomeFingers    = "012335567888"
+	isoBottomFingers  = "00123355678"
+)
+
+// row creates a row of Keys. The i-th digit of fingers is the Finger of the
+// i-th Key. The i-th rune of base, shift and altGr is the rune the i-th Key
+// produces on the respective Level, where a space means, that the Key
+// produces nothing on that Level. shift and altGr may be shorter than base. It
+// panics, if base and fingers differ in length
+func row(r int, fingers, base, shift, altGr string) []Key {
+	f := []rune(fingers)
+	b := []rune(base)
+	if len(f) != len(b) {
+		panic("layout: " + base + " doesn't match " + fingers)
+	}
+	keys := make([]Key, len(b))
+	for i := range b {
+		keys[i] = Key{
+			Row:    r,
+			Column: i,
+			Finger: Finger(f[i] - '0'),
+		}
+		for level, runes := range []string{base, shift, altGr} {
+			rs := []rune(runes)
+			if i < len(rs) && rs[i] != ' ' {
+				keys[i].Levels[level] = rs[i]
+			}
+		}
+	}
+	return keys
+}
+
+func space() []Key {
+	return []Key{{Row: SpaceRow, Finger: Thumbs, Levels: [levels]rune{' ', ' '}}}
+}
@@ -0,0 +1,86 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestLayouts(t *testing.T) {
+	for _, l := range All() {
+		seen := make(map[rune]bool)
+		for i, row := range l.Rows {
+			for j, k := range row {
+				assert.True(t, k.Finger >= LeftLittle && k.Finger <= RightLittle, l.ID+": "+string(k.Rune()))
+				assert.Equal(t, i, k.Row, l.ID+": "+string(k.Rune()))
+				assert.Equal(t, j, k.Column, l.ID+": "+string(k.Rune()))
+				assert.NotZero(t, k.Rune(), l.ID)
+				assert.False(t, seen[k.Rune()], l.ID+": duplicate "+string(k.Rune()))
+				seen[k.Rune()] = true
+			}
+		}
+		for r := 'a'; r <= 'z'; r++ {
+			// every layout can type the latin alphabet
+			_, _, ok := l.Key(r)
+			assert.True(t, ok, l.ID+": "+string(r))
+		}
+		found, ok := ByID(l.ID)
+		assert.True(t, ok)
+		assert.Equal(t, l, found)
+	}
+	_, ok := ByID("unknown")
+	assert.False(t, ok)
+}
+
+func TestKey(t *testing.T) {
+	k, level, ok := US.Key('Y')
+	assert.True(t, ok)
+	assert.Equal(t, Shift, level)
+	assert.Equal(t, 'y', k.Rune())
+	assert.Equal(t, TopRow, k.Row)
+	assert.Equal(t, 5, k.Column)
+
+	k, level, _ = German.Key('€')
+	assert.Equal(t, AltGr, level)
+	assert.Equal(t, 'e', k.Rune())
+
+	f, ok := US.Finger('y')
+	assert.True(t, ok)
+	assert.Equal(t, RightIndex, f)
+	f, _ = German.Finger('y')
+	assert.Equal(t, LeftLittle, f)
+	f, _ = German.Finger('Ö')
+	assert.Equal(t, RightLittle, f)
+	f, _ = Dvorak.Finger('u')
+	assert.Equal(t, LeftIndex, f)
+	f, _ = US.Finger(' ')
+	assert.Equal(t, Thumbs, f)
+	_, ok = US.Finger('ö')
+	assert.False(t, ok)
+	assert.Equal(t, "left ring finger", LeftRing.String())
+}
+
+func TestCharacters(t *testing.T) {
+	home := func(k Key) bool {
+		return k.Row == HomeRow
+	}
+	assert.Equal(t, []rune("asdfghjkl"), US.Characters(Base, func(k Key) bool {
+		return home(k) && Letters(k)
+	}))
+	assert.Equal(t, []rune("ARSTDHNEIO"), Colemak.Characters(Shift, func(k Key) bool {
+		return home(k) && Letters(k)
+	}))
+	assert.Equal(t, []rune("qsdfghjklmù*"), French.Characters(Base, home))
+	assert.Equal(t, []rune("@€~"), German.Characters(AltGr, func(k Key) bool {
+		return k.Row == TopRow
+	}))
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t, German, Default(language.German))
+	assert.Equal(t, French, Default(language.French))
+	assert.Equal(t, UK, Default(language.BritishEnglish))
+	assert.Equal(t, US, Default(language.English))
+	assert.Equal(t, US, Default(language.Japanese))
+}
@@ -0,0 +1,165 @@
+package locale
+
+var german = map[string]string{
+	// config-page
+	"Play":                    "Spielen",
+	"History":                 "Verlauf",
+	"Multiplayer":             "Mehrspieler",
+	"Offline":                 "Offline",
+	"Copy Link":               "Link kopieren",
+	"Copy this link:":         "Kopiere diesen Link:",
+	"no game-modes available": "keine Spielmodi verfügbar",
+
+	"Random":                             "Zufall",
+	"Speed-Test with random characters.": "Geschwindigkeitstest mit zufälligen Zeichen.",
+	"Dictionary":                         "Wörterbuch",
+	"Speed-Test with words from a dictionary.": "Geschwindigkeitstest mit Wörtern aus einem Wörterbuch.",
+
+	"Keyboard Layout":              "Tastaturbelegung",
+	"The layout of your keyboard.": "Die Belegung deiner Tastatur.",
+	"English (US)":                 "Englisch (US)",
+	"English (UK)":                 "Englisch (UK)",
+	"German (QWERTZ)":              "Deutsch (QWERTZ)",
+	"French (AZERTY)":              "Französisch (AZERTY)",
+	"Dvorak":                       "Dvorak",
+	"Colemak":                      "Colemak",
+
+	"Charset": "Zeichensatz",
+	"The characters contained in the model-text.": "Die Zeichen, die der Vorlagetext enthält.",
+	"Inner Line":         "Grundreihe",
+	"Index Fingers":      "Zeigefinger",
+	"Middle Fingers":     "Mittelfinger",
+	"Ring Fingers":       "Ringfinger",
+	"Little Fingers":     "Kleine Finger",
+	"Thumbs":             "Daumen",
+	"Upper-Case-Letters": "Großbuchstaben",
+
+	"Training":                           "Training",
+	"How the characters are chosen.":     "Wie die Zeichen ausgewählt werden.",
+	"Adaptive: Practice Weak Characters": "Adaptiv: Schwache Zeichen üben",
+
+	"Duration": "Dauer",
+	"When the game ends. Time is measured from the first keystroke.": "Wann das Spiel endet. Die Zeit läuft ab dem ersten Tastendruck.",
+	"%d Seconds":                 "%d Sekunden",
+	"%d Characters":              "%d Zeichen",
+	"%d Words":                   "%d Wörter",
+	"Until First Error":          "Bis zum ersten Fehler",
+	"Custom":                     "Eigene",
+	"Custom duration in seconds": "Eigene Dauer in Sekunden",
+
+	"Language":                        "Sprache",
+	"The language of the dictionary.": "Die Sprache des Wörterbuchs.",
+	"English":                         "Englisch",
+	"German":                          "Deutsch",
+
+	"Word Length":                        "Wortlänge",
+	"The amount of characters per word.": "Die Anzahl an Zeichen pro Wort.",
+	"Any":                                "Beliebig",
+	"Short (up to 4)":                    "Kurz (bis 4)",
+	"Medium (5 to 7)":                    "Mittel (5 bis 7)",
+	"Long (8 or more)":                   "Lang (8 oder mehr)",
+
+	"Word Frequency":            "Worthäufigkeit",
+	"How common the words are.": "Wie gebräuchlich die Wörter sind.",
+	"All":                       "Alle",
+	"Most Common 100":           "Häufigste 100",
+	"Less Common":               "Seltenere",
+
+	"Capitalization": "Großschreibung",
+	"Whether words start with an upper-case-letter.": "Ob Wörter mit einem Großbuchstaben beginnen.",
+	"lower case":  "klein",
+	"Capitalized": "Großgeschrieben",
+	"Mixed":       "Gemischt",
+
+	// game-page
+	"characters per minute":   "Zeichen pro Minute",
+	"words per minute":        "Wörter pro Minute",
+	"failure rate":            "Fehlerquote",
+	"Shift":                   "Umschalt",
+	"AltGr":                   "Alt Gr",
+	"Paused":                  "Pausiert",
+	"Resume":                  "Fortsetzen",
+	"Quit":                    "Beenden",
+	"ahead by %d characters":  "%d Zeichen voraus",
+	"behind by %d characters": "%d Zeichen zurück",
+	"head to head":            "Kopf an Kopf",
+
+	// results-page
+	"Retry Same Text":                       "Gleichen Text wiederholen",
+	"New Text":                              "Neuer Text",
+	"Race Your Ghost":                       "Gegen den eigenen Geist antreten",
+	"Back to Settings":                      "Zurück zu den Einstellungen",
+	"Watch Replay":                          "Aufzeichnung ansehen",
+	"Fast Replay":                           "Aufzeichnung im Zeitraffer",
+	"Copy Replay Link":                      "Link zur Aufzeichnung kopieren",
+	"net words per minute":                  "Netto-Wörter pro Minute",
+	"gross words per minute":                "Brutto-Wörter pro Minute",
+	"accuracy":                              "Genauigkeit",
+	"no misses":                             "keine Fehler",
+	"most missed":                           "häufigste Fehler",
+	"misses per key":                        "Fehler pro Taste",
+	"not typed":                             "nicht getippt",
+	"words per minute over time (max %.0f)": "Wörter pro Minute im Verlauf (max. %.0f)",
+	"%d of %d missed, %dms average latency": "%d von %d verfehlt, %dms durchschnittliche Latenz",
+	"%.0f":                                  "%.0f",
+	"%.1f%%":                                "%.1f%%",
+	"%.2f%%":                                "%.2f%%",
+	"%dms":                                  "%dms",
+	"left little finger":                    "linker kleiner Finger",
+	"left ring finger":                      "linker Ringfinger",
+	"left middle finger":                    "linker Mittelfinger",
+	"left index finger":                     "linker Zeigefinger",
+	"thumbs":                                "Daumen",
+	"right index finger":                    "rechter Zeigefinger",
+	"right middle finger":                   "rechter Mittelfinger",
+	"right ring finger":                     "rechter Ringfinger",
+	"right little finger":                   "rechter kleiner Finger",
+
+	// history-page
+	"Clear History":       "Verlauf löschen",
+	"no games played yet": "noch keine Spiele gespielt",
+	"personal bests":      "persönliche Bestleistungen",
+	"%.0f wpm":            "%.0f WpM",
+	"%s: %d games, %.0f words per minute, %.2f%% accuracy": "%s: %d Spiele, %.0f Wörter pro Minute, %.2f%% Genauigkeit",
+	"words per minute per day from %s to %s (max %.0f)":    "Wörter pro Minute pro Tag vom %s bis %s (max. %.0f)",
+	"accuracy per day from %s to %s":                       "Genauigkeit pro Tag vom %s bis %s",
+
+	// lobby-page
+	"Your Name":                              "Dein Name",
+	"Room Code":                              "Raumcode",
+	"Create Room":                            "Raum erstellen",
+	"Join Room":                              "Raum beitreten",
+	"Start Race":                             "Rennen starten",
+	"Leave":                                  "Verlassen",
+	"host":                                   "Gastgeber",
+	"Please enter your name.":                "Bitte gib deinen Namen ein.",
+	"waiting for the host to start the race": "warte darauf, dass der Gastgeber das Rennen startet",
+	"You won the race!":                      "Du hast das Rennen gewonnen!",
+	"%s won the race.":                       "%s hat das Rennen gewonnen.",
+
+	// error-page
+	"reload":                        "neu laden",
+	"something unexpected happened": "etwas Unerwartetes ist passiert",
+	"the game is already running":   "das Spiel läuft bereits",
+	"a critical error occurred while communicating with the backend": "bei der Kommunikation mit dem Backend ist ein kritischer Fehler aufgetreten",
+	"the connection to the api-backend failed":                       "die Verbindung zum API-Backend ist fehlgeschlagen",
+	"the backend-api is only a test- or development-build":           "die Backend-API ist nur ein Test- oder Entwicklungs-Build",
+	"the returned response doesn't match the expected type":          "die Antwort entspricht nicht dem erwarteten Typ",
+	"the given arguments don't match the requirements":               "die übergebenen Argumente erfüllen die Anforderungen nicht",
+	"the server doesn't know the reqested stream-type":               "der Server kennt den angeforderten Stream-Typ nicht",
+	"the server couldn't find a stream with the given id":            "der Server konnte keinen Stream mit der angegebenen ID finden",
+	"the configuration is not valid for this type of stream":         "die Konfiguration ist für diesen Stream-Typ nicht gültig",
+	"there is no local implementation for the requested stream-type": "für den angeforderten Stream-Typ gibt es keine lokale Implementierung",
+	"the model-input-stream contained an illegal character":          "der Vorlagetext enthielt ein ungültiges Zeichen",
+	"the stored configuration is invalid":                            "die gespeicherte Konfiguration ist ungültig",
+	"the stored configuration was created by a newer version":        "die gespeicherte Konfiguration wurde von einer neueren Version erstellt",
+	"the stored history is invalid":                                  "der gespeicherte Verlauf ist ungültig",
+	"the stored history was created by a newer version":              "der gespeicherte Verlauf wurde von einer neueren Version erstellt",
+	"the recording is invalid":                                       "die Aufzeichnung ist ungültig",
+	"the recording was created by a newer version":                   "die Aufzeichnung wurde von einer neueren Version erstellt",
+	"the request was canceled":                                       "die Anfrage wurde abgebrochen",
+	"the connection to the stream was lost":                          "die Verbindung zum Stream wurde unterbrochen",
+	"the connection to the stream was lost, reconnecting":            "die Verbindung zum Stream wurde unterbrochen, verbinde erneut",
+	"the lobby rejected the request":                                 "die Lobby hat die Anfrage abgelehnt",
+	"the connection to the lobby was lost":                           "die Verbindung zur Lobby wurde unterbrochen",
+}
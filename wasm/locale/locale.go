@@ -0,0 +1,97 @@
+// Package locale translates all user-facing strings. Messages are identified by
+// their English text, which is also used, if a translation is missing
+package locale
+
+import (
+	"strings"
+
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Fallback is the language of the message-keys
+var Fallback = language.English
+
+// Languages are all languages, the interface is available in. The first one
+// is the Fallback
+var Languages = []language.Tag{
+	language.English,
+	language.German,
+}
+
+// dictionaries hold the translations of all messages per language except the
+// Fallback
+var dictionaries = map[language.Tag]map[string]string{
+	language.German: german,
+}
+
+// names are the languages' names in the respective language
+var names = map[language.Tag]string{
+	language.English: "English",
+	language.German:  "Deutsch",
+}
+
+var (
+	messages = newCatalog()
+	matcher  = language.NewMatcher(Languages)
+	current  = Fallback
+	printer  = message.NewPrinter(current, message.Catalog(messages))
+)
+
+func newCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(Fallback))
+	for tag, d := range dictionaries {
+		for key, msg := range d {
+			if err := b.SetString(tag, key, msg); err != nil {
+				panic("locale: " + key + ": " + err.Error())
+			}
+		}
+	}
+	return b
+}
+
+// Match returns the supported language, that matches the given one best
+func Match(tag language.Tag) language.Tag {
+	t, _, _ := matcher.Match(tag)
+	return t
+}
+
+// SetLanguage sets the language, T translates to. The language is matched
+// against the supported Languages. SetLanguage must not be called concurrently
+// with T
+func SetLanguage(tag language.Tag) {
+	current = Match(tag)
+	printer = message.NewPrinter(current, message.Catalog(messages))
+}
+
+// Language returns the language set by SetLanguage
+func Language() language.Tag {
+	return current
+}
+
+// Name returns the given language's name in that language
+func Name(tag language.Tag) string {
+	if n, ok := names[tag]; ok {
+		return n
+	}
+	return tag.String()
+}
+
+// T translates the message identified by key to the current language. The
+// key is a format-string as used by fmt.Sprintf, which is applied to args
+func T(key string, args ...interface{}) string {
+	return printer.Sprintf(key, args...)
+}
+
+// Error translates the given Error's message. Appended descriptions are
+// preserved as they are
+func Error(e errors.Error) string {
+	base := e.Type().Error()
+	msg := e.Error()
+	if strings.HasPrefix(msg, base) {
+		return T(base) + msg[len(base):]
+	}
+	return msg
+}
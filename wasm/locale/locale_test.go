@@ -0,0 +1,117 @@
+package locale
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"golang.org/x/text/language"
+)
+
+// keys collects all message-keys used in the wasm-sources, i.e. the literal
+// arguments of T and the messages of errors.New, as well as the names of
+// layouts and fingers, which are translated dynamically
+func keys(t *testing.T) map[string]bool {
+	keys := make(map[string]bool)
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok || !(pkg.Name == "locale" && sel.Sel.Name == "T" || pkg.Name == "errors" && sel.Sel.Name == "New") {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				key, err := strconv.Unquote(lit.Value)
+				assert.Nil(t, err)
+				keys[key] = true
+			}
+			return true
+		})
+		return nil
+	})
+	assert.Nil(t, err)
+	for _, l := range layout.All() {
+		keys[l.Name] = true
+	}
+	for f := layout.LeftLittle; f <= layout.RightLittle; f++ {
+		keys[f.String()] = true
+	}
+	return keys
+}
+
+var verb = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func verbs(format string) []string {
+	v := verb.FindAllString(format, -1)
+	sort.Strings(v)
+	return v
+}
+
+func TestCatalogComplete(t *testing.T) {
+	used := keys(t)
+	assert.True(t, used["Play"])
+	assert.True(t, used["the connection to the api-backend failed"])
+	for tag, d := range dictionaries {
+		for key := range used {
+			translation, ok := d[key]
+			if assert.True(t, ok, tag.String()+" is missing "+strconv.Quote(key)) {
+				assert.Equal(t, verbs(key), verbs(translation), tag.String()+": "+strconv.Quote(key))
+			}
+		}
+		for key := range d {
+			assert.True(t, used[key], tag.String()+" contains unused "+strconv.Quote(key))
+		}
+	}
+	for _, l := range Languages {
+		_, ok := names[l]
+		assert.True(t, ok, l.String())
+		if l != Fallback {
+			_, ok = dictionaries[l]
+			assert.True(t, ok, l.String())
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(Fallback)
+
+	assert.Equal(t, "Play", T("Play"))
+	assert.Equal(t, "15 Seconds", T("%d Seconds", 15))
+
+	SetLanguage(language.MustParse("de-AT"))
+	assert.Equal(t, language.German, Language())
+	assert.Equal(t, "Spielen", T("Play"))
+	assert.Equal(t, "15 Sekunden", T("%d Seconds", 15))
+	assert.Equal(t, "unknown", T("unknown"))
+	assert.Equal(t, "Deutsch", Name(language.German))
+
+	err := errors.New("the stored history is invalid", errors.Warning).Append("details")
+	assert.Equal(t, "der gespeicherte Verlauf ist ungültig (details)", Error(err))
+
+	SetLanguage(language.Japanese)
+	assert.Equal(t, Fallback, Language())
+}
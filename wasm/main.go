@@ -1,17 +1,35 @@
+//go:build js && wasm
+// +build js,wasm
+
 package main
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/dennwc/dom/js"
 	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/communication"
 	"github.com/theMomax/notypo-frontend/wasm/comparison"
 	"github.com/theMomax/notypo-frontend/wasm/config"
 	"github.com/theMomax/notypo-frontend/wasm/errors"
 	"github.com/theMomax/notypo-frontend/wasm/game"
+	"github.com/theMomax/notypo-frontend/wasm/history"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
 	"github.com/theMomax/notypo-frontend/wasm/ui"
 )
 
+// fastReplaySpeed is how many times faster than recorded a fast replay is
+// played back
+const fastReplaySpeed = 4
+
+// reportInterval is how often the user's progress is sent to the other players
+// of a race
+const reportInterval = time.Second
+
 func main() {
 	defer func() {
 		recover()
@@ -22,9 +40,33 @@ func main() {
 	ui.OnPlay(func() {
 		switch config.Game.StreamSupplierDescription().Type {
 		case api.Random, api.Dictionary:
-			starter <- handleSinglePlayerGame
+			starter <- func() {
+				playSinglePlayerGames(nil)
+			}
 		}
 	})
+	ui.OnMultiplayer(func() {
+		starter <- playMultiplayer
+	})
+	// a shared replay-link is played back right away
+	if rec, err := ui.RequestedReplay(); err != nil {
+		go func() {
+			starter <- func() {
+				ui.EP.Print(locale.Error(err))
+				ui.Visit(ui.EP)
+			}
+		}()
+	} else if rec != nil {
+		go func() {
+			starter <- func() {
+				r := watchReplay(rec, 1)
+				ui.GP.ClearGame()
+				if r != nil {
+					playSinglePlayerGames(r)
+				}
+			}
+		}()
+	}
 	for {
 		s := <-starter
 		s()
@@ -35,59 +77,428 @@ func main() {
 	}
 }
 
-func handleSinglePlayerGame() {
+// playSinglePlayerGames runs single-player-games until the user returns to the
+// config-page. After each game, the results are presented. If r isn't nil, its
+// results are presented before the first game
+func playSinglePlayerGames(r *result) {
+	var text []comparison.Character
+	// ghost is the best run on text, if the user races against it
+	var ghost *result
+	for {
+		if r == nil {
+			var rec *game.Recording
+			if ghost != nil {
+				rec = ghost.recording
+			}
+			r = handleSinglePlayerGame(text, rec)
+			ui.GP.ClearGame()
+			if r == nil {
+				return
+			}
+		}
+		switch presentResults(r) {
+		case ui.Retry:
+			text = r.model
+			ghost = nil
+		case ui.RaceGhost:
+			text = r.model
+			if ghost == nil || netWPM(r) > netWPM(ghost) {
+				ghost = r
+			}
+		case ui.NewText:
+			text = nil
+			ghost = nil
+		default:
+			return
+		}
+		r = nil
+	}
+}
+
+func netWPM(r *result) float64 {
+	return comparison.RatesFor(r.stats, r.elapsed).NetWPM()
+}
+
+// presentResults displays the given result until the user decides how to
+// continue. Replays requested by the user are played back in between
+func presentResults(r *result) ui.Choice {
+	for {
+		ui.Visit(ui.RP)
+		choice := ui.RP.Present(r.stats, comparison.RatesFor(r.stats, r.elapsed), r.analysis, r.layout, r.recording)
+		switch choice {
+		case ui.Replay:
+			watchReplay(r.recording, 1)
+		case ui.FastReplay:
+			watchReplay(r.recording, fastReplaySpeed)
+		default:
+			return choice
+		}
+		ui.GP.ClearGame()
+	}
+}
+
+// result holds the outcome of a single game
+type result struct {
+	// model is the complete model-text received
+	model    []comparison.Character
+	stats    comparison.Statistics
+	analysis *analysis.Analysis
+	layout   *layout.Layout
+	elapsed  time.Duration
+	// recording allows to replay the game
+	recording *game.Recording
+}
+
+// handleSinglePlayerGame runs a single game, whose model-text starts with the
+// given text. If ghost isn't nil, it is raced against. The function returns
+// nil, if the game was aborted by the user or due to an error, or if the user
+// didn't type at all
+func handleSinglePlayerGame(text []comparison.Character, ghost *game.Recording) *result {
 	ui.Visit(ui.GP)
-	var started bool
+	description := config.Game.Description()
+	ui.GP.SetLayout(description.KeyboardLayout())
+	end := description.End
 	var errorOccurred bool
-	exit := make(chan interface{})
-	ui.GP.OnExit(func() {
-		go game.Stop()
+	aborted := make(chan bool, 1)
+	var modelText []comparison.Character
+
+	model := game.ModelInputProvider(description, warn)
+	if text != nil {
+		model = game.RepeatInputProvider(text, model)
+	}
+	attempt := game.AttemptInputProvider(arrayOfCharacters(append(description.Charset, comparison.BS)...))
+	session := game.NewSession(end, model, attempt)
+
+	// the rates are refreshed periodically, so they decrease while the user
+	// doesn't type. Paused time doesn't count
+	var stats comparison.Statistics
+	keystrokes := analysis.New()
+	var statsMutex sync.Mutex
+	updateRates := func() {
+		statsMutex.Lock()
+		defer statsMutex.Unlock()
+		if stats != nil {
+			showRates(stats, comparison.RatesFor(stats, session.Elapsed()))
+		}
+	}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	tickerDone := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				updateRates()
+			case <-tickerDone:
+				return
+			}
+		}
+	}()
+
+	// the ghost starts with the user's first keystroke
+	var g *game.Ghost
+	ghostDone := make(chan bool)
+	if ghost != nil {
+		g = game.NewGhost(ghost)
+		ui.GP.SetGhost(0)
+	}
+	startGhost := func() {
+		if g == nil {
+			return
+		}
+		g.Start()
 		go func() {
-			exit <- true
+			defer close(ghostDone)
+			for p := range g.Positions() {
+				ui.GP.SetGhost(p)
+			}
 		}()
-	})
+	}
 
-	game.HandleGame(&config.Game,
-		func() <-chan comparison.Character {
-			return game.ModelInputProvider(config.Game.StreamSupplierDescription())
-		},
-		func() <-chan comparison.Character {
-			return game.AttemptInputProvider(arrayOfCharacters(append(config.Game.StreamSupplierDescription().Charset, comparison.BS)...), func(c api.Character) {
-				if !started {
-					started = true
-					ui.GP.SetTimer(time.Minute)
-					time.AfterFunc(time.Minute, game.Stop)
-				}
-			})
-		}, func(c comparison.Character) {
-			ui.GP.CreateCharacter(c)
-		}, func(c comparison.Comparison) {
-			if len(c.Changes()) == 1 {
-				change := c.Changes()[0]
-				if change.Deletion() {
-					ui.GP.DeleteChar()
-				} else {
-					ui.GP.TypeChar(c.State().Correct())
+	ui.GP.OnPause(func() {
+		session.Pause()
+		if g != nil {
+			g.Pause()
+		}
+	})
+	ui.GP.OnResume(func() {
+		session.Resume()
+		if g != nil {
+			g.Resume()
+		}
+	})
+	ui.GP.OnExit(func() {
+		select {
+		case aborted <- true:
+		default:
+		}
+		session.Stop()
+	})
+	session.Start()
+	for e := range session.Events() {
+		switch e.Type {
+		case game.ModelCharacter:
+			modelText = append(modelText, e.Character)
+			ui.GP.CreateCharacter(e.Character)
+		case game.Compared:
+			c := e.Comparison
+			if stats == nil {
+				if end.Duration > 0 {
+					ui.GP.SetTimer(end.Duration)
 				}
+				startGhost()
+			}
+			display(c)
+			statsMutex.Lock()
+			stats = c.Statistics()
+			for _, change := range c.Changes() {
+				keystrokes.Add(change)
 			}
-			ui.GP.SetCPM(float64(c.Statistics().CorrectCharacters()))
-			ui.GP.SetWPM(float64(c.Statistics().CorrectWords()))
-			ui.GP.SetFR(float64(c.Statistics().FailureRate()))
-		}, func(e errors.Error) {
+			statsMutex.Unlock()
+			updateRates()
+		case game.Failed:
 			errorOccurred = true
-			ui.EP.Print(e.Error())
-			if e.Is(errors.Critical) {
-				game.Stop()
+			ui.EP.Print(locale.Error(e.Err))
+			if e.Err.Is(errors.Critical) {
 				ui.Visit(ui.EP)
 			}
-		})
-	if !errorOccurred {
+		}
+	}
+	ticker.Stop()
+	close(tickerDone)
+	updateRates()
+	if g != nil && stats != nil {
+		g.Stop()
+		<-ghostDone
+	}
+
+	select {
+	case <-aborted:
+		return nil
+	default:
+	}
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+	if errorOccurred || stats == nil {
+		return nil
+	}
+	elapsed := session.Elapsed()
+	if end.Duration > 0 && elapsed > end.Duration {
+		elapsed = end.Duration
+	}
+	ui.HP.Record(history.NewRecord(time.Now(), description, stats, keystrokes, elapsed))
+	recording := session.Recording()
+	recording.Layout = description.KeyboardLayout().ID
+	return &result{
+		model:     modelText,
+		stats:     stats,
+		analysis:  keystrokes,
+		layout:    description.KeyboardLayout(),
+		elapsed:   elapsed,
+		recording: recording,
+	}
+}
+
+// watchReplay plays back the given Recording on the game-page speed times as
+// fast as it was recorded. It returns the replayed game's result, or nil, if
+// the replay was aborted by the user or due to an error
+func watchReplay(rec *game.Recording, speed float64) *result {
+	ui.Visit(ui.GP)
+	l, ok := layout.ByID(rec.Layout)
+	if !ok {
+		l = layout.US
+	}
+	ui.GP.SetLayout(l)
+	replay := game.NewReplay(rec, speed)
+	session := game.NewSession(config.EndCondition{}, replay.Model(), replay.Attempt())
+	aborted := make(chan bool, 1)
+	ui.GP.OnPause(replay.Pause)
+	ui.GP.OnResume(replay.Resume)
+	ui.GP.OnExit(func() {
 		select {
-		case <-time.After(30 * time.Second):
-		case <-exit:
+		case aborted <- true:
+		default:
 		}
+		session.Stop()
+	})
+
+	var modelText []comparison.Character
+	var stats comparison.Statistics
+	var errorOccurred bool
+	keystrokes := analysis.New()
+	session.Start()
+	for e := range session.Events() {
+		switch e.Type {
+		case game.ModelCharacter:
+			modelText = append(modelText, e.Character)
+			ui.GP.CreateCharacter(e.Character)
+		case game.Compared:
+			c := e.Comparison
+			if stats == nil && rec.Duration > 0 {
+				ui.GP.SetTimer(time.Duration(float64(rec.Duration) / speed))
+			}
+			display(c)
+			stats = c.Statistics()
+			for _, change := range c.Changes() {
+				keystrokes.Add(change)
+				// the replayed keystrokes carry their recorded time
+				showRates(stats, comparison.RatesAt(stats, change.Time()))
+			}
+		case game.Failed:
+			errorOccurred = true
+			ui.EP.Print(locale.Error(e.Err))
+			if e.Err.Is(errors.Critical) {
+				ui.Visit(ui.EP)
+			}
+		}
+	}
+
+	select {
+	case <-aborted:
+		return nil
+	default:
+	}
+	if errorOccurred || stats == nil {
+		return nil
+	}
+	return &result{
+		model:     modelText,
+		stats:     stats,
+		analysis:  keystrokes,
+		layout:    l,
+		elapsed:   rec.Duration,
+		recording: rec,
+	}
+}
+
+// playMultiplayer connects to the lobby and lets the user create or join rooms
+// and race the other players until they leave the lobby
+func playMultiplayer() {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Backend.Timeout)
+	lobby, err := config.Backend.Client().ConnectLobby(ctx)
+	cancel()
+	if err != nil {
+		ui.EP.Print(locale.Error(err))
+		ui.Visit(ui.EP)
+		return
 	}
-	ui.GP.ClearGame()
+	defer lobby.Close()
+	ui.LP.Reset()
+	ui.Visit(ui.LP)
+	var player string
+	for {
+		select {
+		case r := <-ui.LP.Requests():
+			switch r.Type {
+			case ui.CreateRoom:
+				player = r.Player
+				err = lobby.Create(player, config.Game.StreamSupplierDescription())
+			case ui.JoinRoom:
+				player = r.Player
+				err = lobby.Join(r.RoomID, player)
+			case ui.StartRace:
+				err = lobby.Start()
+			case ui.LeaveLobby:
+				lobby.Leave()
+				return
+			}
+			if err != nil {
+				ui.LP.Print(locale.Error(err))
+			}
+		case m, ok := <-lobby.Messages():
+			if !ok {
+				ui.EP.Print(locale.Error(communication.ErrLobbyConnectionLost))
+				return
+			}
+			switch m.Type {
+			case communication.RoomUpdated:
+				ui.LP.ShowRoom(m.Room, player)
+			case communication.LobbyFailure:
+				ui.LP.Print(locale.Error(m.Err()))
+			case communication.RaceStarted:
+				winner := handleRace(player, lobby, m.StreamID)
+				ui.GP.ClearGame()
+				ui.Visit(ui.LP)
+				switch winner {
+				case "":
+				case player:
+					ui.LP.Print(locale.T("You won the race!"))
+				default:
+					ui.LP.Print(locale.T("%s won the race.", winner))
+				}
+			}
+		}
+	}
+}
+
+// handleRace runs the race on the stream with the given id for the given
+// player. It returns the winner's name, or an empty string, if the user left
+// the race or it failed
+func handleRace(player string, lobby *communication.Lobby, streamID int64) (winner string) {
+	ui.Visit(ui.GP)
+	description := config.Game.Description()
+	ui.GP.SetLayout(description.KeyboardLayout())
+	attempt := game.AttemptInputProvider(arrayOfCharacters(append(description.Charset, comparison.BS)...))
+	race := game.NewRace(player, lobby, game.SharedInputProvider(streamID, warn), attempt, reportInterval)
+
+	// the other players don't wait, so escape leaves the race right away
+	ui.GP.SetPausable(false)
+	ui.GP.OnExit(race.Stop)
+	if err := race.Start(); err != nil {
+		ui.EP.Print(locale.Error(err))
+		return ""
+	}
+	for e := range race.Events() {
+		switch e.Type {
+		case game.ModelCharacter:
+			ui.GP.CreateCharacter(e.Character)
+		case game.Compared:
+			c := e.Comparison
+			display(c)
+			showRates(c.Statistics(), comparison.RatesFor(c.Statistics(), race.Elapsed()))
+		case game.Standings:
+			// eliminated players are crossed out in the standings
+			ui.GP.SetStandings(e.Players, player)
+		case game.Finished:
+			winner = e.Player
+		case game.Failed:
+			ui.EP.Print(locale.Error(e.Err))
+			if e.Err.Is(errors.Critical) {
+				ui.Visit(ui.EP)
+			}
+		}
+	}
+	return winner
+}
+
+// warn displays the given non-critical error on the game-page. A nil error
+// hides it
+func warn(err errors.Error) {
+	if err == nil {
+		ui.GP.Warn("")
+		return
+	}
+	ui.GP.Warn(locale.Error(err))
+}
+
+// display shows the given Comparison on the game-page
+func display(c comparison.Comparison) {
+	if len(c.Changes()) == 1 {
+		change := c.Changes()[0]
+		if change.Deletion() {
+			ui.GP.DeleteChar()
+		} else {
+			ui.GP.TypeChar(c.State().Correct())
+		}
+	}
+	for _, change := range c.Changes() {
+		ui.GP.Press(change)
+	}
+}
+
+func showRates(s comparison.Statistics, r comparison.Rates) {
+	ui.GP.SetCPM(r.CPM())
+	ui.GP.SetWPM(r.NetWPM())
+	ui.GP.SetFR(s.FailureRate())
 }
 
 func arrayOfCharacters(items ...api.BasicCharacter) []api.Character {
@@ -0,0 +1,98 @@
+package streams
+
+import (
+	"math/rand"
+	"unicode"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+	"golang.org/x/text/language"
+)
+
+// dictionarySource picks random words from the dictionary's subset, which
+// only consists of Characters contained in the charset and matches the
+// config.DictionaryOptions. The words are separated by spaces
+type dictionarySource struct {
+	words          [][]api.BasicCharacter
+	current        []api.BasicCharacter
+	capitalization config.Capitalization
+	allowed        map[rune]bool
+}
+
+func newDictionarySource(charset []api.BasicCharacter, options config.DictionaryOptions) (source, errors.Error) {
+	s := &dictionarySource{
+		words:          make([][]api.BasicCharacter, 0),
+		capitalization: options.Capitalization,
+		allowed:        make(map[rune]bool, len(charset)),
+	}
+	for _, c := range charset {
+		s.allowed[c.Rune()] = true
+	}
+	for rank, w := range dictionary(options.Language) {
+		if rank < options.MinRank || (options.MaxRank > 0 && rank >= options.MaxRank) {
+			continue
+		}
+		word, ok := convert(w, s.allowed)
+		if !ok {
+			continue
+		}
+		// the converted word contains a trailing space
+		length := len(word) - 1
+		if length < options.MinLength || (options.MaxLength > 0 && length > options.MaxLength) {
+			continue
+		}
+		s.words = append(s.words, word)
+	}
+	if len(s.words) == 0 {
+		return nil, ErrIllegalConfiguration.Append("no word matches the given charset and options")
+	}
+	return s, nil
+}
+
+func (s *dictionarySource) next(r *rand.Rand) api.BasicCharacter {
+	if len(s.current) == 0 {
+		s.current = s.words[r.Intn(len(s.words))]
+		if s.capitalization == config.Capitalized || (s.capitalization == config.MixedCase && r.Intn(2) == 0) {
+			s.current = s.capitalize(s.current)
+		}
+	}
+	c := s.current[0]
+	s.current = s.current[1:]
+	return c
+}
+
+// capitalize returns a copy of word, where the first letter is in upper case,
+// if the upper case letter is allowed
+func (s *dictionarySource) capitalize(word []api.BasicCharacter) []api.BasicCharacter {
+	u := unicode.ToUpper(word[0].Rune())
+	if !s.allowed[u] {
+		return word
+	}
+	c := make([]api.BasicCharacter, len(word))
+	copy(c, word)
+	c[0] = api.BasicCharacter(u)
+	return c
+}
+
+// dictionary returns the embedded word-list for the given language. English
+// is used as the default
+func dictionary(l language.Tag) []string {
+	if base, _ := l.Base(); base.String() == "de" {
+		return german
+	}
+	return english
+}
+
+// convert returns word followed by a space as a slice of Characters. ok is
+// false, if word contains a Character, that is not allowed
+func convert(word string, allowed map[rune]bool) (converted []api.BasicCharacter, ok bool) {
+	converted = make([]api.BasicCharacter, 0, len(word)+1)
+	for _, r := range word {
+		if !allowed[r] || unicode.IsSpace(r) {
+			return nil, false
+		}
+		converted = append(converted, api.BasicCharacter(r))
+	}
+	return append(converted, ' '), true
+}
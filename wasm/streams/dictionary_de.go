@@ -0,0 +1,33 @@
+package streams
+
+// german contains common German words ordered by descending frequency
+var german = []string{
+	"der", "die", "und", "in", "den", "von", "zu", "das", "mit", "sich", "des",
+	"auf", "für", "ist", "im", "dem", "nicht", "ein", "eine", "als", "auch", "es",
+	"an", "werden", "aus", "er", "hat", "dass", "sie", "nach", "wird", "bei",
+	"einer", "um", "am", "sind", "noch", "wie", "einem", "über", "einen", "so",
+	"zum", "war", "haben", "nur", "oder", "aber", "vor", "zur", "bis", "mehr",
+	"durch", "man", "sein", "wurde", "sei", "ihr", "ich", "wir", "schon", "wenn",
+	"ihre", "kann", "dann", "unter", "jahr", "diese", "gegen", "können", "da",
+	"uns", "hier", "immer", "jetzt", "ohne", "zwei", "alle", "sehr", "weil",
+	"heute", "gut", "neue", "machen", "viel", "kinder", "schule", "haus", "zeit",
+	"tag", "mann", "frau", "welt", "land", "stadt", "leben", "wasser", "hand",
+	"auge", "kopf", "freund", "spiel", "buch", "baum", "tier", "hund", "katze",
+	"vogel", "fisch", "blume", "garten", "straße", "auto", "ball", "bild", "wort",
+	"brief", "name", "frage", "antwort", "lied", "musik", "farbe", "licht",
+	"nacht", "morgen", "abend", "woche", "monat", "sommer", "winter", "frühling",
+	"herbst", "sonne", "mond", "stern", "himmel", "erde", "feuer", "luft",
+	"regen", "schnee", "wind", "berg", "fluss", "see", "meer", "wald", "feld",
+	"weg", "tür", "fenster", "tisch", "stuhl", "bett", "zimmer", "küche", "brot",
+	"milch", "käse", "apfel", "kuchen", "essen", "trinken", "spielen", "lesen",
+	"schreiben", "rechnen", "laufen", "gehen", "kommen", "sehen", "hören",
+	"sagen", "fragen", "lachen", "singen", "tanzen", "malen", "schlafen",
+	"wohnen", "lernen", "helfen", "finden", "geben", "nehmen", "halten",
+	"bleiben", "stehen", "sitzen", "liegen", "fahren", "fliegen", "schwimmen",
+	"klein", "groß", "alt", "jung", "lang", "kurz", "schnell", "langsam", "hoch",
+	"tief", "warm", "kalt", "hell", "dunkel", "laut", "leise", "schön", "froh",
+	"müde", "stark", "rot", "blau", "grün", "gelb", "weiß", "schwarz", "eins",
+	"drei", "vier", "fünf", "sechs", "sieben", "acht", "neun", "zehn", "mutter",
+	"vater", "bruder", "schwester", "oma", "opa", "familie", "lehrer", "klasse",
+	"pause", "tafel", "heft", "stift", "papier",
+}
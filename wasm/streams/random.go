@@ -0,0 +1,61 @@
+package streams
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// randomSource picks each Character randomly from its charset. The probability
+// of each Character is proportional to its weight
+type randomSource struct {
+	charset []api.BasicCharacter
+	// cumulative holds the sum of the weights of charset[0] to charset[i] at
+	// index i. It is nil, if all Characters have the same weight
+	cumulative []float64
+}
+
+func newRandomSource(charset []api.BasicCharacter, weights map[rune]float64) (source, errors.Error) {
+	if len(charset) == 0 {
+		return nil, ErrIllegalConfiguration.Append("empty charset")
+	}
+	s := &randomSource{
+		charset: charset,
+	}
+	if len(weights) == 0 {
+		return s, nil
+	}
+	s.cumulative = make([]float64, len(charset))
+	sum := 0.0
+	for i, c := range charset {
+		w, ok := weights[c.Rune()]
+		if !ok {
+			w = 1
+		}
+		if w < 0 {
+			return nil, ErrIllegalConfiguration.Append("negative weight for '" + string(c.Rune()) + "'")
+		}
+		sum += w
+		s.cumulative[i] = sum
+	}
+	if sum == 0 {
+		return nil, ErrIllegalConfiguration.Append("all weights are zero")
+	}
+	return s, nil
+}
+
+func (s *randomSource) next(r *rand.Rand) api.BasicCharacter {
+	if s.cumulative == nil {
+		return s.charset[r.Intn(len(s.charset))]
+	}
+	x := r.Float64() * s.cumulative[len(s.cumulative)-1]
+	i := sort.Search(len(s.cumulative), func(i int) bool {
+		return s.cumulative[i] > x
+	})
+	if i == len(s.charset) {
+		i--
+	}
+	return s.charset[i]
+}
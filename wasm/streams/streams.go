@@ -0,0 +1,64 @@
+// Package streams generates model-streams on the client-side. It is an
+// alternative to the backend's streams, which doesn't require a connection to
+// the server
+package streams
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+)
+
+// errors
+var (
+	ErrStreamNotImplemented = errors.New("there is no local implementation for the requested stream-type", errors.Critical)
+	ErrIllegalConfiguration = errors.New("the configuration is not valid for this type of stream", errors.Critical)
+)
+
+// source produces the model-text Character by Character
+type source interface {
+	next(r *rand.Rand) api.BasicCharacter
+}
+
+// Options returns the StreamSourceTypes, that can be generated locally
+func Options() api.StreamOptionsResponse {
+	return api.StreamOptionsResponse{api.Random, api.Dictionary}
+}
+
+// Open returns a channel of Characters with the given buffer and spawns a new
+// goroutine, which generates the stream described by description and pipes it
+// into the returned channel. This process ends, when the goroutine receives a
+// signal from the given done channel, or done is closed
+func Open(description *config.Description, buffer uint, done <-chan bool) (<-chan comparison.Character, errors.Error) {
+	var src source
+	var err errors.Error
+	switch description.Type {
+	case api.Random:
+		src, err = newRandomSource(description.Charset, description.Weights)
+	case api.Dictionary:
+		src, err = newDictionarySource(description.Charset, description.Dictionary)
+	default:
+		return nil, ErrStreamNotImplemented.Append(string(description.Type))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mod := make(chan comparison.Character, int(buffer))
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	go func() {
+		defer close(mod)
+		for {
+			select {
+			case mod <- src.next(r):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return mod, nil
+}
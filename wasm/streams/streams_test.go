@@ -0,0 +1,132 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"golang.org/x/text/language"
+)
+
+func TestRandom(t *testing.T) {
+	done := make(chan bool)
+	mod, err := Open(description(api.Random, "abc"), 10, done)
+	assert.Nil(t, err)
+	for _, c := range read(mod, 1000) {
+		assert.Contains(t, "abc", string(c))
+	}
+	close(done)
+	consume(mod)
+}
+
+func TestWeightedRandom(t *testing.T) {
+	d := description(api.Random, "abc")
+	d.Weights = map[rune]float64{'a': 8, 'c': 0}
+	done := make(chan bool)
+	mod, err := Open(d, 10, done)
+	assert.Nil(t, err)
+	count := make(map[rune]int)
+	for _, c := range read(mod, 9000) {
+		count[c]++
+	}
+	close(done)
+	consume(mod)
+	// 'b' has the default weight of 1
+	assert.InDelta(t, 8000, count['a'], 400)
+	assert.InDelta(t, 1000, count['b'], 400)
+	assert.Equal(t, 0, count['c'])
+}
+
+func TestDictionary(t *testing.T) {
+	done := make(chan bool)
+	mod, err := Open(description(api.Dictionary, "theofand "), 10, done)
+	assert.Nil(t, err)
+	text := string(read(mod, 1000))
+	done <- true
+	consume(mod)
+	words := strings.Fields(text)
+	assert.True(t, len(words) > 100)
+	// the last word may be incomplete
+	for _, w := range words[:len(words)-1] {
+		assert.Contains(t, english, w)
+		assert.Empty(t, strings.Trim(w, "theofand"))
+	}
+}
+
+func TestDictionaryOptions(t *testing.T) {
+	d := description(api.Dictionary, "abcdefghijklmnopqrstuvwxyzäöüßABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÜ ")
+	d.Dictionary = config.DictionaryOptions{
+		Language:       language.German,
+		MinLength:      4,
+		MaxLength:      6,
+		MaxRank:        150,
+		Capitalization: config.Capitalized,
+	}
+	done := make(chan bool)
+	mod, err := Open(d, 10, done)
+	assert.Nil(t, err)
+	words := strings.Fields(string(read(mod, 1000)))
+	close(done)
+	consume(mod)
+	for _, w := range words[:len(words)-1] {
+		r := []rune(w)
+		assert.True(t, len(r) >= 4 && len(r) <= 6, w)
+		assert.True(t, unicode.IsUpper(r[0]), w)
+		rank := index(german, strings.ToLower(string(r[:1]))+string(r[1:]))
+		assert.True(t, rank >= 0 && rank < 150, w)
+	}
+}
+
+func TestIllegalConfiguration(t *testing.T) {
+	_, err := Open(description(api.Random, ""), 10, nil)
+	assert.Equal(t, ErrIllegalConfiguration.Type(), err.Type())
+	_, err = Open(description(api.Dictionary, "xq"), 10, nil)
+	assert.Equal(t, ErrIllegalConfiguration.Type(), err.Type())
+	d := description(api.Random, "ab")
+	d.Weights = map[rune]float64{'a': 0, 'b': 0}
+	_, err = Open(d, 10, nil)
+	assert.Equal(t, ErrIllegalConfiguration.Type(), err.Type())
+	d.Weights = map[rune]float64{'a': -1}
+	_, err = Open(d, 10, nil)
+	assert.Equal(t, ErrIllegalConfiguration.Type(), err.Type())
+	_, err = Open(description("other", "xq"), 10, nil)
+	assert.Equal(t, ErrStreamNotImplemented.Type(), err.Type())
+}
+
+// description creates a config.Description of the given type and charset
+func description(t api.StreamSourceType, charset string) *config.Description {
+	d := &config.Description{}
+	d.Type = t
+	for _, r := range charset {
+		d.Charset = append(d.Charset, api.BasicCharacter(r))
+	}
+	return d
+}
+
+// read receives n Characters from c and returns them as a slice
+func read(c <-chan comparison.Character, n int) []rune {
+	s := make([]rune, n)
+	for i := range s {
+		s[i] = (<-c).Rune()
+	}
+	return s
+}
+
+// consume receives from c until it is closed
+func consume(c <-chan comparison.Character) {
+	for range c {
+	}
+}
+
+func index(words []string, w string) int {
+	for i, word := range words {
+		if word == w {
+			return i
+		}
+	}
+	return -1
+}
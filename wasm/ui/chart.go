@@ -0,0 +1,37 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// lineChart draws each of the given series as a line into an svg. The values
+// are scaled, so max is at the top of the chart. All series must have the same
+// length of at least two
+func lineChart(max float64, series ...[]float64) string {
+	var b strings.Builder
+	b.WriteString(`<svg viewBox="0 0 100 40" preserveAspectRatio="none">`)
+	for i, values := range series {
+		b.WriteString(`<polyline class="series` + strconv.Itoa(i) + `" points="`)
+		for j, v := range values {
+			x := 100 * float64(j) / float64(len(values)-1)
+			y := 40 - 38*v/max
+			b.WriteString(strconv.FormatFloat(x, 'f', 2, 64) + "," + strconv.FormatFloat(y, 'f', 2, 64) + " ")
+		}
+		b.WriteString(`"/>`)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func maximum(values []float64) (max float64) {
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return
+}
@@ -1,6 +1,10 @@
+//go:build js && wasm
+// +build js,wasm
+
 package ui
 
 import (
+	"context"
 	"net/url"
 	"sort"
 	"strings"
@@ -11,6 +15,9 @@ import (
 	"github.com/theMomax/notypo-backend/api"
 	com "github.com/theMomax/notypo-frontend/wasm/communication"
 	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+	"github.com/theMomax/notypo-frontend/wasm/streams"
 	"golang.org/x/text/language"
 )
 
@@ -23,22 +30,47 @@ type ConfigPage struct {
 	startWrapper  *dom.Element
 	playButton    *dom.Button
 	onPlay        []func()
+	onMultiplayer []func()
 	optionpages   []page
 }
 
 type option interface {
+	// ID identifies the option within its game-type. It is used for
+	// persisting the option's state and must therefore never change
+	ID() string
 	Description() string
 	EnabledByDefault() bool
 	OnEnable() func()
 	OnDisable() func()
 }
 
+// describable is implemented by options, whose description changes over time.
+// update is called with the new description on every change
+type describable interface {
+	option
+	OnDescriptionChange(update func(string))
+}
+
+// configurable is implemented by options, which provide an input-element for
+// adjusting their value
+type configurable interface {
+	option
+	Input() dom.Node
+}
+
 type setting interface {
 	Name() string
 	Description() string
 	Options() []option
 }
 
+// choice is implemented by settings, of which exactly one option is enabled
+// at a time
+type choice interface {
+	setting
+	choice()
+}
+
 type gameType interface {
 	SST() api.StreamSourceType
 	Name() string
@@ -57,7 +89,7 @@ func initConfigPage() *ConfigPage {
 		typeWrapper:   dom.Doc.GetElementById("game_types"),
 		optionWrapper: dom.Doc.GetElementById("game_options"),
 		startWrapper:  dom.Doc.GetElementById("game_start"),
-		playButton:    dom.NewButton("Play"),
+		playButton:    dom.NewButton(locale.T("Play")),
 		optionpages:   make([]page, 0),
 	}
 	u, err := url.Parse(js.Get("window").Get("location").Get("href").String())
@@ -65,36 +97,51 @@ func initConfigPage() *ConfigPage {
 		EP.Print(err.Error())
 		Visit(EP)
 	}
-	m := language.NewMatcher([]language.Tag{
-		language.English,
-		language.German,
-	})
-	langstring := language.English.String()
-	if len(u.Query()["lang"]) > 0 {
-		langstring = u.Query()["lang"][0]
-	}
-	tag, err := language.Parse(langstring)
-	if err != nil {
-		EP.Print(err.Error())
-		tag = language.English
+	cp.lang = locale.Language()
+	dom.Doc.GetElementById("languages").AppendChild(newLanguageSwitcher(u))
+
+	loadState()
+	if state, ok := config.StateFromQuery(u.Query()); ok {
+		// a shared link overrides the persisted configuration
+		config.Game.Restore(state)
 	}
-	cp.lang, _, _ = m.Match(tag)
 
 	settings = make(map[api.StreamSourceType]gameType)
-	settings[api.Random] = &random{cp.lang}
+	settings[api.Random] = &random{cp.lang, newLayoutSelection(cp.lang)}
+	settings[api.Dictionary] = &dictionary{cp.lang, newLayoutSelection(cp.lang)}
 
 	cp.playButton.OnClick(func(e dom.Event) {
 		for _, c := range cp.onPlay {
 			c()
 		}
 	})
-	cp.startWrapper.AppendChild(cp.playButton)
 
-	types, err := com.StreamOptions(config.Backend.BaseURL)
-	if err != nil {
-		EP.Print(err.Error())
-		Visit(EP)
+	types, optErr := config.Backend.API().StreamOptions(context.Background())
+	if optErr != nil {
+		if config.Game.Source() != config.Remote && optErr.Type() == com.ErrServerConnectionFailed.Type() {
+			// fall back to the stream-types, that can be generated locally
+			types = streams.Options()
+			config.Game.SetSource(config.Local)
+		} else {
+			EP.Print(locale.Error(optErr))
+			Visit(EP)
+		}
 	}
+	cp.startWrapper.AppendChild(newToggleButton(&offline{}, config.Game.Source() == config.Local, nil))
+	cp.startWrapper.AppendChild(newShareButton(u, cp.lang))
+	historyButton := dom.NewButton(locale.T("History"))
+	historyButton.OnClick(func(dom.Event) {
+		go Visit(HP)
+	})
+	cp.startWrapper.AppendChild(historyButton)
+	multiplayerButton := dom.NewButton(locale.T("Multiplayer"))
+	multiplayerButton.OnClick(func(dom.Event) {
+		for _, c := range cp.onMultiplayer {
+			c()
+		}
+	})
+	cp.startWrapper.AppendChild(multiplayerButton)
+	cp.startWrapper.AppendChild(cp.playButton)
 	relevantTypes := make([]gameType, 0)
 	for _, t := range types {
 		if s, ok := settings[t]; ok {
@@ -107,22 +154,31 @@ func initConfigPage() *ConfigPage {
 
 func (cp *ConfigPage) buildPage(relevantTypes []gameType) {
 	if len(relevantTypes) == 0 {
-		EP.Print("no game-modes available")
+		EP.Print(locale.T("no game-modes available"))
 		Visit(EP)
 	}
+	initialType := defaultStreamSourceType
+	if _, ok := settings[config.Game.Type()]; ok {
+		initialType = config.Game.Type()
+	}
+	var defaultPage page
 	for _, t := range relevantTypes {
+		t := t
 		b := dom.NewButton(t.Name())
 		p := initTabFromElement(&b.Element, cp.buildOptionsPage(t))
 		b.OnClick(func(dom.Event) {
 			cp.visit(p)
 			config.Game.SetType(t.SST())
+			saveState()
 		})
-		if t.SST() == defaultStreamSourceType {
+		if t.SST() == initialType || defaultPage == nil {
 			config.Game.SetType(t.SST())
+			defaultPage = p
 		}
 		cp.typeWrapper.AppendChild(b)
-		cp.optionpages = append(cp.optionpages)
+		cp.optionpages = append(cp.optionpages, p)
 	}
+	cp.visit(defaultPage)
 }
 
 func (cp *ConfigPage) buildOptionsPage(t gameType) page {
@@ -142,30 +198,120 @@ func (cp *ConfigPage) buildOptionsPage(t gameType) page {
 		sd.SetInnerHTML(s.Description())
 		sd.ClassList().Add("description")
 		settings.AppendChild(sd)
+		if _, ok := s.(choice); ok {
+			for _, b := range newChoiceButtons(t.SST(), s.Options()) {
+				settings.AppendChild(b)
+			}
+		} else {
+			for _, o := range s.Options() {
+				settings.AppendChild(newOptionButton(t.SST(), o))
+			}
+		}
 		for _, o := range s.Options() {
-			opt := dom.NewButton(o.Description())
-			if o.EnabledByDefault() {
-				opt.ClassList().Add("active")
-				o.OnEnable()()
+			if c, ok := o.(configurable); ok {
+				settings.AppendChild(c.Input())
 			}
-			func(o option) {
-				opt.OnClick(func(e dom.Event) {
-					if strings.Contains(" "+opt.GetAttribute("class").String()+" ", " active ") {
-						opt.ClassList().Remove("active")
-						o.OnDisable()()
-					} else {
-						opt.ClassList().Add("active")
-						o.OnEnable()()
-					}
-				})
-			}(o)
-			settings.AppendChild(opt)
 		}
 	}
 	cp.optionWrapper.AppendChild(p)
 	return initPageFromElement(p)
 }
 
+// newOptionButton creates a button, which enables or disables the given
+// option of the given game-type on click. The option's state is recorded in
+// config.Game and persisted
+func newOptionButton(t api.StreamSourceType, o option) *dom.Button {
+	enabled := initiallyEnabled(t, o)
+	config.Game.SetOption(t, o.ID(), enabled)
+	return newToggleButton(o, enabled, func(enabled bool) {
+		config.Game.SetOption(t, o.ID(), enabled)
+		saveState()
+	})
+}
+
+// newToggleButton creates a button, which enables or disables the given
+// option on click and calls onToggle afterwards, if it is not nil
+func newToggleButton(o option, enabled bool, onToggle func(enabled bool)) *dom.Button {
+	opt := dom.NewButton(o.Description())
+	if d, ok := o.(describable); ok {
+		d.OnDescriptionChange(func(description string) {
+			opt.SetInnerHTML(description)
+		})
+	}
+	if enabled {
+		opt.ClassList().Add("active")
+		o.OnEnable()()
+	}
+	opt.OnClick(func(e dom.Event) {
+		enabled := !strings.Contains(" "+opt.GetAttribute("class").String()+" ", " active ")
+		if enabled {
+			opt.ClassList().Add("active")
+			o.OnEnable()()
+		} else {
+			opt.ClassList().Remove("active")
+			o.OnDisable()()
+		}
+		if onToggle != nil {
+			onToggle(enabled)
+		}
+	})
+	return opt
+}
+
+// newChoiceButtons creates a button for each of the given options of the given
+// game-type. Clicking a button enables its option and disables the one enabled
+// before. Initially, the option enabled in the persisted state is enabled, or
+// else the first option enabled by default
+func newChoiceButtons(t api.StreamSourceType, options []option) []*dom.Button {
+	buttons := make([]*dom.Button, len(options))
+	active := -1
+	for i, o := range options {
+		buttons[i] = dom.NewButton(o.Description())
+		if enabled, known := config.Game.Option(t, o.ID()); known && enabled {
+			active = i
+		}
+	}
+	for i, o := range options {
+		if active == -1 && o.EnabledByDefault() {
+			active = i
+		}
+	}
+	if active == -1 {
+		active = 0
+	}
+	for i, o := range options {
+		config.Game.SetOption(t, o.ID(), i == active)
+	}
+	buttons[active].ClassList().Add("active")
+	options[active].OnEnable()()
+	for i := range options {
+		i := i
+		buttons[i].OnClick(func(e dom.Event) {
+			if i == active {
+				return
+			}
+			buttons[active].ClassList().Remove("active")
+			options[active].OnDisable()()
+			config.Game.SetOption(t, options[active].ID(), false)
+			buttons[i].ClassList().Add("active")
+			options[i].OnEnable()()
+			config.Game.SetOption(t, options[i].ID(), true)
+			active = i
+			saveState()
+		})
+	}
+	return buttons
+}
+
+// initiallyEnabled returns whether the given option of the given game-type is
+// enabled according to the persisted state, or else by default
+func initiallyEnabled(t api.StreamSourceType, o option) bool {
+	if enabled, known := config.Game.Option(t, o.ID()); known {
+		return enabled
+	}
+	return o.EnabledByDefault()
+}
+
 func (cp *ConfigPage) visit(target page) {
 	for _, p := range cp.optionpages {
 		if p != target {
@@ -180,8 +326,13 @@ func (cp *ConfigPage) registerOnPlay(callback func()) {
 	cp.onPlay = append(cp.onPlay, callback)
 }
 
+func (cp *ConfigPage) registerOnMultiplayer(callback func()) {
+	cp.onMultiplayer = append(cp.onMultiplayer, callback)
+}
+
 type random struct {
-	lang language.Tag
+	lang   language.Tag
+	layout *layoutSelection
 }
 
 func (r *random) SST() api.StreamSourceType {
@@ -189,51 +340,74 @@ func (r *random) SST() api.StreamSourceType {
 }
 
 func (r *random) Name() string {
-	return "Random"
+	return locale.T("Random")
 }
 
 func (r *random) Description() string {
-	return "Speed-Test with random characters."
+	return locale.T("Speed-Test with random characters.")
 }
 
 func (r *random) Settings() []setting {
-	return []setting{&charset{api.Random, r.lang}}
+	return []setting{
+		&keyboardLayout{api.Random, r.layout},
+		&charset{api.Random, r.layout, true},
+		&training{api.Random},
+		&duration{api.Random},
+	}
 }
 
 type charset struct {
-	sst  api.StreamSourceType
-	lang language.Tag
+	sst       api.StreamSourceType
+	layout    *layoutSelection
+	upperCase bool
 }
 
 func (c *charset) Name() string {
-	return "Charset"
+	return locale.T("Charset")
 }
 
 func (c *charset) Description() string {
-	return "The characters contained in the model-text."
+	return locale.T("The characters contained in the model-text.")
 }
 
 func (c *charset) Options() []option {
-	return []option{
-		&charsetoption{inner{}, c.sst, c.lang, nil},
-		&charsetoption{indexfingers{}, c.sst, c.lang, nil},
-		&charsetoption{middlefingers{}, c.sst, c.lang, nil},
-		&charsetoption{ringfingers{}, c.sst, c.lang, nil},
-		&charsetoption{littlefingers{}, c.sst, c.lang, nil},
-		&charsetoption{thumbs{}, c.sst, c.lang, nil},
-		&shift{c.sst, nil},
+	options := []option{
+		&charsetoption{charsetgroup{"inner", locale.T("Inner Line"), homeRow}, c.sst, c.layout, nil},
+		&charsetoption{charsetgroup{"indexfingers", locale.T("Index Fingers"), typedWith(layout.LeftIndex, layout.RightIndex)}, c.sst, c.layout, nil},
+		&charsetoption{charsetgroup{"middlefingers", locale.T("Middle Fingers"), typedWith(layout.LeftMiddle, layout.RightMiddle)}, c.sst, c.layout, nil},
+		&charsetoption{charsetgroup{"ringfingers", locale.T("Ring Fingers"), typedWith(layout.LeftRing, layout.RightRing)}, c.sst, c.layout, nil},
+		&charsetoption{charsetgroup{"littlefingers", locale.T("Little Fingers"), typedWith(layout.LeftLittle, layout.RightLittle)}, c.sst, c.layout, nil},
+		&charsetoption{charsetgroup{"thumbs", locale.T("Thumbs"), typedWith(layout.Thumbs)}, c.sst, c.layout, nil},
 	}
+	if c.upperCase {
+		options = append(options, &shift{c.sst, nil})
+	}
+	return options
 }
 
 type charsetoption struct {
-	charsetprovider
+	charsetgroup
 	sst         api.StreamSourceType
-	lang        language.Tag
+	layout      *layoutSelection
 	modificator *int64
 }
 
+func (c *charsetoption) ID() string {
+	return c.id
+}
+
 func (c *charsetoption) Description() string {
-	return c.description(c.lang) + ": " + charsetDescription(c.chars(c.lang))
+	return c.describe(c.layout.current)
+}
+
+func (c *charsetoption) describe(l *layout.Layout) string {
+	return c.description + ": " + charsetDescription(c.chars(l))
+}
+
+func (c *charsetoption) OnDescriptionChange(update func(string)) {
+	c.layout.onChange(func(l *layout.Layout) {
+		update(c.describe(l))
+	})
 }
 
 func (c *charsetoption) EnabledByDefault() bool {
@@ -243,8 +417,13 @@ func (c *charsetoption) EnabledByDefault() bool {
 func (c *charsetoption) OnEnable() func() {
 	return func() {
 		if c.modificator == nil {
-			id := config.Game.AddModificator(c.sst, func(ssd *api.StreamSupplierDescription) {
-				ssd.Charset = extend(ssd.Charset, c.chars(c.lang))
+			id := config.Game.AddModificator(config.Modificator{
+				Type:  c.sst,
+				Name:  "charset-" + c.id,
+				Stage: config.Base,
+				Action: func(ssd *config.Description) {
+					ssd.Charset = extend(ssd.Charset, c.chars(ssd.KeyboardLayout()))
+				},
 			})
 			c.modificator = &id
 		}
@@ -260,131 +439,164 @@ func (c *charsetoption) OnDisable() func() {
 	}
 }
 
-type charsetprovider interface {
-	chars(language.Tag) []api.BasicCharacter
-	description(language.Tag) string
+// charsetgroup is a group of keys, whose characters are practiced together.
+// The characters depend on the keyboard's layout
+type charsetgroup struct {
+	id          string
+	description string
+	accepts     func(layout.Key) bool
 }
 
-type inner struct{}
-
-func (i inner) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{'a', 's', 'd', 'f', 'g', 'h', 'j', 'k', 'l', 'ö'}
-	default:
-		return []api.BasicCharacter{'a', 's', 'd', 'f', 'g', 'h', 'j', 'k', 'l'}
+// chars returns the base-level characters of the keys of the given Layout,
+// which belong to the group
+func (g charsetgroup) chars(l *layout.Layout) []api.BasicCharacter {
+	runes := l.Characters(layout.Base, func(k layout.Key) bool {
+		return practiced(k) && g.accepts(k)
+	})
+	chars := make([]api.BasicCharacter, len(runes))
+	for i, r := range runes {
+		chars[i] = api.BasicCharacter(r)
 	}
+	return chars
 }
 
-func (i inner) description(l language.Tag) string {
-	return "Inner Line"
+// practiced returns whether the key's character may be part of a charset.
+// Apart from letters, only space, comma and period are practiced
+func practiced(k layout.Key) bool {
+	return layout.Letters(k) || strings.ContainsRune(" ,.", k.Rune())
 }
 
-type indexfingers struct{}
+func homeRow(k layout.Key) bool {
+	return k.Row == layout.HomeRow
+}
 
-func (i indexfingers) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{'r', 'f', 'z', 'u', 'c', 'v', 'b', 'n', 'm'}
-	default:
-		return []api.BasicCharacter{'r', 'f', 'y', 'u', 'c', 'v', 'b', 'n', 'm'}
+// typedWith returns a filter accepting the keys typed with one of the given
+// fingers
+func typedWith(fingers ...layout.Finger) func(layout.Key) bool {
+	return func(k layout.Key) bool {
+		for _, f := range fingers {
+			if k.Finger == f {
+				return true
+			}
+		}
+		return false
 	}
 }
 
-func (i indexfingers) description(l language.Tag) string {
-	return "Index Fingers"
+type shift struct {
+	sst         api.StreamSourceType
+	modificator *int64
 }
 
-type middlefingers struct{}
-
-func (m middlefingers) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{'e', 'd', 'i', 'k', ','}
-	default:
-		return []api.BasicCharacter{'e', 'd', 'i', 'k', ','}
-	}
+func (s *shift) ID() string {
+	return "shift"
 }
 
-func (m middlefingers) description(l language.Tag) string {
-	return "Middle Fingers"
+func (s *shift) Description() string {
+	return locale.T("Upper-Case-Letters")
 }
 
-type ringfingers struct{}
+func (s *shift) EnabledByDefault() bool {
+	return true
+}
 
-func (r ringfingers) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{'w', 's', 'x', 'o', 'l'}
-	default:
-		return []api.BasicCharacter{'w', 's', 'x', 'o', 'l'}
+func (s *shift) OnEnable() func() {
+	return func() {
+		if s.modificator == nil {
+			id := config.Game.AddModificator(config.Modificator{
+				Type:  s.sst,
+				Name:  s.ID(),
+				Stage: config.Derive,
+				Action: func(ssd *config.Description) {
+					ssd.Charset = extend(ssd.Charset, upperCase(ssd.Charset))
+				},
+			})
+			s.modificator = &id
+		}
 	}
 }
 
-func (r ringfingers) description(l language.Tag) string {
-	return "Ring Fingers"
+func (s *shift) OnDisable() func() {
+	return func() {
+		if s.modificator != nil {
+			config.Game.RemoveModificator(*s.modificator)
+			s.modificator = nil
+		}
+	}
 }
 
-type littlefingers struct{}
+// modificatoroption is an option, which registers a fixed modificator, while
+// it is enabled
+type modificatoroption struct {
+	sst              api.StreamSourceType
+	id               string
+	description      string
+	enabledByDefault bool
+	stage            config.Stage
+	priority         int
+	action           func(*config.Description)
+	modificator      *int64
+}
 
-func (li littlefingers) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{'q', 'y', 'ß', 'p', 'ü', 'ö', 'ä', '.'}
-	default:
-		return []api.BasicCharacter{'q', 'z', 'p', '.'}
-	}
+func (m *modificatoroption) ID() string {
+	return m.id
 }
 
-func (li littlefingers) description(l language.Tag) string {
-	return "Little Fingers"
+func (m *modificatoroption) Description() string {
+	return m.description
 }
 
-type thumbs struct{}
+func (m *modificatoroption) EnabledByDefault() bool {
+	return m.enabledByDefault
+}
 
-func (t thumbs) chars(l language.Tag) []api.BasicCharacter {
-	switch l {
-	case language.German:
-		return []api.BasicCharacter{' '}
-	default:
-		return []api.BasicCharacter{' '}
+func (m *modificatoroption) OnEnable() func() {
+	return func() {
+		if m.modificator == nil {
+			id := config.Game.AddModificator(config.Modificator{
+				Type:     m.sst,
+				Name:     m.id,
+				Stage:    m.stage,
+				Priority: m.priority,
+				Action:   m.action,
+			})
+			m.modificator = &id
+		}
 	}
 }
 
-func (t thumbs) description(l language.Tag) string {
-	return "Thumbs"
+func (m *modificatoroption) OnDisable() func() {
+	return func() {
+		if m.modificator != nil {
+			config.Game.RemoveModificator(*m.modificator)
+			m.modificator = nil
+		}
+	}
 }
 
-type shift struct {
-	sst         api.StreamSourceType
-	modificator *int64
+type offline struct{}
+
+func (o *offline) ID() string {
+	return "offline"
 }
 
-func (s *shift) Description() string {
-	return "Upper-Case-Letters"
+func (o *offline) Description() string {
+	return locale.T("Offline")
 }
 
-func (s *shift) EnabledByDefault() bool {
-	return true
+func (o *offline) EnabledByDefault() bool {
+	return config.Game.Source() == config.Local
 }
 
-func (s *shift) OnEnable() func() {
+func (o *offline) OnEnable() func() {
 	return func() {
-		if s.modificator == nil {
-			id := config.Game.AddModificator(s.sst, func(ssd *api.StreamSupplierDescription) {
-				ssd.Charset = extend(ssd.Charset, upperCase(ssd.Charset))
-			}, true)
-			s.modificator = &id
-		}
+		config.Game.SetSource(config.Local)
 	}
 }
 
-func (s *shift) OnDisable() func() {
+func (o *offline) OnDisable() func() {
 	return func() {
-		if s.modificator != nil {
-			config.Game.RemoveModificator(*s.modificator)
-			s.modificator = nil
-		}
+		config.Game.SetSource(config.Auto)
 	}
 }
 
@@ -0,0 +1,183 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+	"golang.org/x/text/language"
+)
+
+type dictionary struct {
+	lang   language.Tag
+	layout *layoutSelection
+}
+
+func (d *dictionary) SST() api.StreamSourceType {
+	return api.Dictionary
+}
+
+func (d *dictionary) Name() string {
+	return locale.T("Dictionary")
+}
+
+func (d *dictionary) Description() string {
+	return locale.T("Speed-Test with words from a dictionary.")
+}
+
+func (d *dictionary) Settings() []setting {
+	return []setting{
+		&wordLanguage{d.lang},
+		&keyboardLayout{api.Dictionary, d.layout},
+		&charset{api.Dictionary, d.layout, false},
+		&wordLength{},
+		&wordFrequency{},
+		&capitalization{},
+		&duration{api.Dictionary},
+	}
+}
+
+type wordLanguage struct {
+	lang language.Tag
+}
+
+func (w *wordLanguage) choice() {}
+
+func (w *wordLanguage) Name() string {
+	return locale.T("Language")
+}
+
+func (w *wordLanguage) Description() string {
+	return locale.T("The language of the dictionary.")
+}
+
+func (w *wordLanguage) Options() []option {
+	return []option{
+		w.option("en", locale.T("English"), language.English),
+		w.option("de", locale.T("German"), language.German),
+	}
+}
+
+func (w *wordLanguage) option(id, description string, l language.Tag) option {
+	return &modificatoroption{
+		sst:              api.Dictionary,
+		id:               "language-" + id,
+		description:      description,
+		enabledByDefault: w.lang == l,
+		action: func(ssd *config.Description) {
+			ssd.Dictionary.Language = l
+			// words are separated by spaces
+			ssd.Charset = extend(ssd.Charset, []api.BasicCharacter{' '})
+		},
+	}
+}
+
+type wordLength struct{}
+
+func (w *wordLength) choice() {}
+
+func (w *wordLength) Name() string {
+	return locale.T("Word Length")
+}
+
+func (w *wordLength) Description() string {
+	return locale.T("The amount of characters per word.")
+}
+
+func (w *wordLength) Options() []option {
+	return []option{
+		w.option("any", locale.T("Any"), 0, 0),
+		w.option("short", locale.T("Short (up to 4)"), 0, 4),
+		w.option("medium", locale.T("Medium (5 to 7)"), 5, 7),
+		w.option("long", locale.T("Long (8 or more)"), 8, 0),
+	}
+}
+
+func (w *wordLength) option(id, description string, min, max int) option {
+	return &modificatoroption{
+		sst:              api.Dictionary,
+		id:               "length-" + id,
+		description:      description,
+		enabledByDefault: min == 0 && max == 0,
+		action: func(ssd *config.Description) {
+			ssd.Dictionary.MinLength = min
+			ssd.Dictionary.MaxLength = max
+		},
+	}
+}
+
+type wordFrequency struct{}
+
+func (w *wordFrequency) choice() {}
+
+func (w *wordFrequency) Name() string {
+	return locale.T("Word Frequency")
+}
+
+func (w *wordFrequency) Description() string {
+	return locale.T("How common the words are.")
+}
+
+func (w *wordFrequency) Options() []option {
+	return []option{
+		w.option("all", locale.T("All"), 0, 0),
+		w.option("common", locale.T("Most Common 100"), 0, 100),
+		w.option("rare", locale.T("Less Common"), 100, 0),
+	}
+}
+
+func (w *wordFrequency) option(id, description string, min, max int) option {
+	return &modificatoroption{
+		sst:              api.Dictionary,
+		id:               "frequency-" + id,
+		description:      description,
+		enabledByDefault: min == 0 && max == 0,
+		action: func(ssd *config.Description) {
+			ssd.Dictionary.MinRank = min
+			ssd.Dictionary.MaxRank = max
+		},
+	}
+}
+
+type capitalization struct{}
+
+func (c *capitalization) choice() {}
+
+func (c *capitalization) Name() string {
+	return locale.T("Capitalization")
+}
+
+func (c *capitalization) Description() string {
+	return locale.T("Whether words start with an upper-case-letter.")
+}
+
+func (c *capitalization) Options() []option {
+	return []option{
+		c.option("lower", locale.T("lower case"), config.LowerCase),
+		c.option("capitalized", locale.T("Capitalized"), config.Capitalized),
+		c.option("mixed", locale.T("Mixed"), config.MixedCase),
+	}
+}
+
+func (c *capitalization) option(id, description string, capitalization config.Capitalization) option {
+	stage := config.Base
+	if capitalization != config.LowerCase {
+		// upper-case-letters are derived from the complete charset
+		stage = config.Derive
+	}
+	return &modificatoroption{
+		sst:              api.Dictionary,
+		id:               "case-" + id,
+		description:      description,
+		enabledByDefault: capitalization == config.LowerCase,
+		stage:            stage,
+		action: func(ssd *config.Description) {
+			ssd.Dictionary.Capitalization = capitalization
+			if capitalization != config.LowerCase {
+				ssd.Charset = extend(ssd.Charset, upperCase(ssd.Charset))
+			}
+		},
+	}
+}
@@ -0,0 +1,134 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dennwc/dom"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// defaultCustomDuration is used by customDuration, until the user enters a
+// different value
+const defaultCustomDuration = 90 * time.Second
+
+type duration struct {
+	sst api.StreamSourceType
+}
+
+func (d *duration) choice() {}
+
+func (d *duration) Name() string {
+	return locale.T("Duration")
+}
+
+func (d *duration) Description() string {
+	return locale.T("When the game ends. Time is measured from the first keystroke.")
+}
+
+func (d *duration) Options() []option {
+	return []option{
+		d.option("15s", locale.T("%d Seconds", 15), false, config.EndCondition{Duration: 15 * time.Second}),
+		d.option("30s", locale.T("%d Seconds", 30), false, config.EndCondition{Duration: 30 * time.Second}),
+		d.option("60s", locale.T("%d Seconds", 60), true, config.EndCondition{Duration: time.Minute}),
+		d.option("120s", locale.T("%d Seconds", 120), false, config.EndCondition{Duration: 2 * time.Minute}),
+		&customDuration{sst: d.sst},
+		d.option("characters-100", locale.T("%d Characters", 100), false, config.EndCondition{Characters: 100}),
+		d.option("words-25", locale.T("%d Words", 25), false, config.EndCondition{Words: 25}),
+		d.option("first-error", locale.T("Until First Error"), false, config.EndCondition{FirstError: true}),
+	}
+}
+
+func (d *duration) option(id, description string, enabledByDefault bool, end config.EndCondition) option {
+	return &modificatoroption{
+		sst:              d.sst,
+		id:               "duration-" + id,
+		description:      description,
+		enabledByDefault: enabledByDefault,
+		action: func(ssd *config.Description) {
+			ssd.End = end
+		},
+	}
+}
+
+// customDuration is a time-based end-condition, whose duration is entered by
+// the user
+type customDuration struct {
+	sst         api.StreamSourceType
+	modificator *int64
+}
+
+func (c *customDuration) ID() string {
+	return "duration-custom"
+}
+
+func (c *customDuration) Description() string {
+	return locale.T("Custom")
+}
+
+func (c *customDuration) EnabledByDefault() bool {
+	return false
+}
+
+func (c *customDuration) OnEnable() func() {
+	return func() {
+		if c.modificator == nil {
+			id := config.Game.AddModificator(config.Modificator{
+				Type:  c.sst,
+				Name:  c.ID(),
+				Stage: config.Base,
+				Action: func(ssd *config.Description) {
+					ssd.End = config.EndCondition{Duration: c.duration()}
+				},
+			})
+			c.modificator = &id
+		}
+	}
+}
+
+func (c *customDuration) OnDisable() func() {
+	return func() {
+		if c.modificator != nil {
+			config.Game.RemoveModificator(*c.modificator)
+			c.modificator = nil
+		}
+	}
+}
+
+// Input creates a number-input for the duration in seconds. Valid values are
+// recorded in config.Game and persisted
+func (c *customDuration) Input() dom.Node {
+	in := dom.NewInput("number")
+	in.SetAttribute("min", 1)
+	in.SetAttribute("title", locale.T("Custom duration in seconds"))
+	in.SetValue(int(c.duration().Seconds()))
+	in.OnChange(func(dom.Event) {
+		seconds, err := strconv.Atoi(in.Value())
+		if err != nil || seconds <= 0 {
+			in.SetValue(int(c.duration().Seconds()))
+			return
+		}
+		config.Game.SetValue(c.sst, c.ID(), strconv.Itoa(seconds))
+		saveState()
+	})
+	return in
+}
+
+// duration returns the recorded duration or defaultCustomDuration, if there is
+// no valid one
+func (c *customDuration) duration() time.Duration {
+	v, known := config.Game.Value(c.sst, c.ID())
+	if !known {
+		return defaultCustomDuration
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds <= 0 {
+		return defaultCustomDuration
+	}
+	return time.Duration(seconds) * time.Second
+}
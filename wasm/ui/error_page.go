@@ -1,3 +1,6 @@
+//go:build js && wasm
+// +build js,wasm
+
 package ui
 
 import (
@@ -6,6 +9,7 @@ import (
 	"github.com/dennwc/dom"
 	"github.com/dennwc/dom/js"
 	"github.com/tevino/abool"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
 )
 
 // ErrorPage represents the page, which prints error-messages
@@ -24,7 +28,7 @@ func initErrorPage() *ErrorPage {
 		cleared: abool.NewBool(true),
 	}
 
-	reload := dom.NewButton("reload")
+	reload := dom.NewButton(locale.T("reload"))
 	reload.OnClick(func(_ dom.Event) {
 		js.Get("window").Get("location").Call("reload", false)
 	})
@@ -1,12 +1,20 @@
+//go:build js && wasm
+// +build js,wasm
+
 package ui
 
 import (
+	"html"
 	"strconv"
 	"time"
 
 	"github.com/dennwc/dom"
 	"github.com/gopherjs/gopherwasm/js"
+	"github.com/tevino/abool"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
 	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
 )
 
 // GamePage represents the page, which displays the actual game
@@ -20,27 +28,75 @@ type GamePage struct {
 	fr             *dom.Element
 	time           *dom.Element
 	cursor         *dom.Element
+	keyboard       *virtualKeyboard
+	ghost          *ghostCursor
+	standings      *dom.Element
+	warning        *dom.Element
+	overlay        *dom.Element
+	paused         *abool.AtomicBool
+	pausable       *abool.AtomicBool
+	loaded         *abool.AtomicBool
 	escapeListener js.Func
 	onExit         func()
+	onPause        func()
+	onResume       func()
 }
 
 // InitGamePage initializes the page, which displays the actual game
 func initGamePage() *GamePage {
 	gp := &GamePage{
-		page:   initPage("game"),
-		stats:  dom.Doc.GetElementById("stats"),
-		done:   dom.Doc.GetElementById("done"),
-		todo:   dom.Doc.GetElementById("todo"),
-		cpm:    dom.Doc.GetElementById("cpm_val"),
-		wpm:    dom.Doc.GetElementById("wpm_val"),
-		fr:     dom.Doc.GetElementById("fr_val"),
-		time:   dom.Doc.GetElementById("time_scale"),
-		cursor: dom.Doc.GetElementById("cursor"),
+		page:      initPage("game"),
+		stats:     dom.Doc.GetElementById("stats"),
+		done:      dom.Doc.GetElementById("done"),
+		todo:      dom.Doc.GetElementById("todo"),
+		cpm:       dom.Doc.GetElementById("cpm_val"),
+		wpm:       dom.Doc.GetElementById("wpm_val"),
+		fr:        dom.Doc.GetElementById("fr_val"),
+		time:      dom.Doc.GetElementById("time_scale"),
+		cursor:    dom.Doc.GetElementById("cursor"),
+		keyboard:  newVirtualKeyboard(dom.Doc.GetElementById("keyboard")),
+		overlay:   dom.Doc.GetElementById("pause"),
+		standings: dom.Doc.GetElementById("standings"),
+		warning:   dom.Doc.GetElementById("warning"),
+		ghost:     newGhostCursor(dom.Doc.GetElementById("done"), dom.Doc.GetElementById("todo"), dom.Doc.GetElementById("ghost")),
+		paused:    abool.New(),
+		pausable:  abool.NewBool(true),
+		loaded:    abool.New(),
+		onExit:    func() {},
+		onPause:   func() {},
+		onResume:  func() {},
 	}
+	dom.Doc.GetElementById("cpm_name").SetInnerHTML(locale.T("characters per minute"))
+	dom.Doc.GetElementById("wpm_name").SetInnerHTML(locale.T("words per minute"))
+	dom.Doc.GetElementById("fr_name").SetInnerHTML(locale.T("failure rate"))
 
+	title := dom.NewElement("p")
+	title.SetInnerHTML(locale.T("Paused"))
+	gp.overlay.AppendChild(title)
+	resume := dom.NewButton(locale.T("Resume"))
+	resume.OnClick(func(_ dom.Event) {
+		gp.Resume()
+	})
+	gp.overlay.AppendChild(resume)
+	quit := dom.NewButton(locale.T("Quit"))
+	quit.OnClick(func(_ dom.Event) {
+		gp.onExit()
+	})
+	gp.overlay.AppendChild(quit)
+
+	// keypress isn't fired for Escape in all browsers
 	gp.escapeListener = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		if args[0].Get("key").String() == "Escape" {
-			gp.onExit()
+			// there is nothing to pause, while the model-text is still
+			// being requested, so the game is left right away. The same
+			// applies to games, that can't be paused at all
+			if !gp.loaded.IsSet() || !gp.pausable.IsSet() {
+				gp.onExit()
+			} else if gp.paused.IsSet() {
+				gp.Resume()
+			} else {
+				gp.Pause()
+			}
 		}
 		return nil
 	})
@@ -50,26 +106,73 @@ func initGamePage() *GamePage {
 
 func (gp *GamePage) Hide() {
 	gp.page.Hide()
-	js.Global().Call("removeEventListener", "keypress", gp.escapeListener)
+	js.Global().Call("removeEventListener", "keydown", gp.escapeListener)
 }
 
 func (gp *GamePage) Show() {
 	gp.page.Show()
-	js.Global().Call("addEventListener", "keypress", gp.escapeListener)
+	js.Global().Call("addEventListener", "keydown", gp.escapeListener)
 }
 
+// OnExit sets the callback, which is called, when the user quits the game
 func (gp *GamePage) OnExit(callback func()) {
 	gp.onExit = callback
 }
 
+// OnPause sets the callback, which is called, after the game was paused
+func (gp *GamePage) OnPause(callback func()) {
+	gp.onPause = callback
+}
+
+// OnResume sets the callback, which is called, after the game was resumed
+func (gp *GamePage) OnResume(callback func()) {
+	gp.onResume = callback
+}
+
+// SetPausable sets whether the current game can be paused. Games, that can't
+// be paused, are left right away, when the user presses escape. ClearGame
+// makes the next game pausable again
+func (gp *GamePage) SetPausable(pausable bool) {
+	gp.pausable.SetTo(pausable)
+}
+
+// Pause shows the pause-overlay and freezes the timer's animation. Pausing a
+// paused game or a game, that can't be paused, has no effect
+func (gp *GamePage) Pause() {
+	if !gp.pausable.IsSet() || !gp.paused.SetToIf(false, true) {
+		return
+	}
+	gp.time.ClassList().Add("paused")
+	gp.overlay.ClassList().Remove("hidden")
+	gp.onPause()
+}
+
+// Resume hides the pause-overlay and continues the timer's animation
+func (gp *GamePage) Resume() {
+	if !gp.paused.SetToIf(true, false) {
+		return
+	}
+	gp.time.ClassList().Remove("paused")
+	gp.overlay.ClassList().Add("hidden")
+	gp.onResume()
+}
+
 func (gp *GamePage) ClearGame() {
+	gp.ghost.Clear()
+	gp.standings.SetInnerHTML("")
+	gp.Warn("")
 	gp.done.SetInnerHTML("")
 	gp.todo.SetInnerHTML("")
 	gp.cpm.SetInnerHTML("0")
 	gp.wpm.SetInnerHTML("0")
 	gp.fr.SetInnerHTML("0%")
 	gp.time.ClassList().Remove("timer")
+	gp.time.ClassList().Remove("paused")
 	gp.time.SetAttribute("style", "")
+	gp.overlay.ClassList().Add("hidden")
+	gp.paused.UnSet()
+	gp.pausable.Set()
+	gp.loaded.UnSet()
 }
 
 func (gp *GamePage) CreateCharacter(item comparison.Character) *dom.Element {
@@ -80,27 +183,69 @@ func (gp *GamePage) CreateCharacter(item comparison.Character) *dom.Element {
 	}
 	e.SetInnerHTML(s)
 	gp.todo.AppendChild(e)
+	gp.keyboard.Append(item)
+	gp.loaded.Set()
 	return e
 }
 
 func (gp *GamePage) TypeChar(correct bool) {
 	gp.PauseCursor()
-	c := gp.todo.ChildNodes()[0]
-	c = gp.todo.RemoveChild(c).(*dom.Element)
-	if correct {
-		c.SetClassName("correct")
-	} else {
-		c.SetClassName("wrong")
-	}
-	gp.done.AppendChild(c)
+	gp.ghost.Move(func() {
+		c := gp.todo.ChildNodes()[0]
+		c = gp.todo.RemoveChild(c).(*dom.Element)
+		if correct {
+			c.SetClassName("correct")
+		} else {
+			c.SetClassName("wrong")
+		}
+		gp.done.AppendChild(c)
+	})
 }
 
 func (gp *GamePage) DeleteChar() {
 	gp.PauseCursor()
-	c := gp.done.ChildNodes()[len(gp.done.ChildNodes())-1]
-	gp.done.RemoveChild(c)
-	c.SetClassName("")
-	gp.todo.SetInnerHTML(c.OuterHTML() + gp.todo.InnerHTML())
+	gp.ghost.Move(func() {
+		c := gp.done.ChildNodes()[len(gp.done.ChildNodes())-1]
+		gp.done.RemoveChild(c)
+		c.SetClassName("")
+		gp.todo.SetInnerHTML(c.OuterHTML() + gp.todo.InnerHTML())
+	})
+}
+
+// Warn displays the given message above the game, e.g. while the connection to
+// the backend is reestablished. An empty message hides the warning
+func (gp *GamePage) Warn(message string) {
+	gp.warning.SetInnerHTML(html.EscapeString(message))
+	if message == "" {
+		gp.warning.ClassList().Add("hidden")
+	} else {
+		gp.warning.ClassList().Remove("hidden")
+	}
+}
+
+// SetStandings lists the players of a multiplayer-race in the given order.
+// The given player's entry is marked
+func (gp *GamePage) SetStandings(players []com.Player, self string) {
+	gp.standings.SetInnerHTML(standings(players, self, ""))
+}
+
+// SetGhost marks the position of a previous run, i.e. the index of its next
+// character within the model-text, and shows the user's lead
+func (gp *GamePage) SetGhost(position int) {
+	gp.ghost.Set(position)
+}
+
+// SetLayout draws the virtual keyboard using the given Layout. It must be
+// called before the game's first model-character is created
+func (gp *GamePage) SetLayout(l *layout.Layout) {
+	gp.keyboard.SetLayout(l)
+}
+
+// Press moves the virtual keyboard's hint to the key of the next expected
+// character and marks the key actually pressed, if the given Modification is a
+// miss
+func (gp *GamePage) Press(m comparison.Modification) {
+	gp.keyboard.Press(m)
 }
 
 func (gp *GamePage) SetWPM(value float64) {
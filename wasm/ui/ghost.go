@@ -0,0 +1,98 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"sync"
+
+	"github.com/dennwc/dom"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// ghostCursor marks the position of a previous run within the typewriter and
+// tells the user how far they are ahead or behind. It is safe for concurrent
+// use
+type ghostCursor struct {
+	mutex     sync.Mutex
+	done      *dom.Element
+	todo      *dom.Element
+	indicator *dom.Element
+	// position is the index of the ghost's next character within the
+	// model-text, or -1, if there is no ghost
+	position int
+	marked   *dom.Element
+}
+
+func newGhostCursor(done, todo, indicator *dom.Element) *ghostCursor {
+	return &ghostCursor{
+		done:      done,
+		todo:      todo,
+		indicator: indicator,
+		position:  -1,
+	}
+}
+
+// Set moves the ghost to the given position
+func (g *ghostCursor) Set(position int) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.unmark()
+	g.position = position
+	g.show()
+}
+
+// Move redraws the ghost around the given function, which changes the user's
+// position. The mark is removed while move runs, so it can't be copied along
+// with the typewriter's characters
+func (g *ghostCursor) Move(move func()) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.unmark()
+	move()
+	g.show()
+}
+
+// Clear removes the ghost
+func (g *ghostCursor) Clear() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.unmark()
+	g.position = -1
+	g.show()
+}
+
+// unmark removes the mark set by show. The caller must hold the mutex
+func (g *ghostCursor) unmark() {
+	if g.marked != nil {
+		g.marked.ClassList().Remove("ghost")
+		g.marked = nil
+	}
+}
+
+// show marks the character in front of the ghost and updates the indicator.
+// The caller must hold the mutex
+func (g *ghostCursor) show() {
+	if g.position < 0 {
+		g.indicator.SetInnerHTML("")
+		return
+	}
+	done := g.done.ChildNodes()
+	todo := g.todo.ChildNodes()
+	lead := len(done) - g.position
+	switch {
+	case lead > 0:
+		g.indicator.SetInnerHTML(locale.T("ahead by %d characters", lead))
+		g.marked = done[len(done)-lead]
+	case lead < 0:
+		g.indicator.SetInnerHTML(locale.T("behind by %d characters", -lead))
+		if -lead < len(todo) {
+			g.marked = todo[-lead]
+		}
+	default:
+		g.indicator.SetInnerHTML(locale.T("head to head"))
+	}
+	if g.marked != nil {
+		g.marked.ClassList().Add("ghost")
+	}
+}
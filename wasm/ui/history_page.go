@@ -0,0 +1,147 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/dennwc/dom"
+	"github.com/dennwc/dom/storage"
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/history"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// HistoryPage represents the page, which displays the results of previous
+// games
+type HistoryPage struct {
+	page
+	charts  *dom.Element
+	bests   *dom.Element
+	actions *dom.Element
+	history *history.History
+}
+
+// initHistoryPage initializes the page, which displays the results of previous
+// games
+func initHistoryPage() *HistoryPage {
+	hp := &HistoryPage{
+		page:    initPage("history"),
+		charts:  dom.Doc.GetElementById("history_charts"),
+		bests:   dom.Doc.GetElementById("history_bests"),
+		actions: dom.Doc.GetElementById("history_actions"),
+	}
+	if s := storage.Local(); s != nil {
+		hp.history = history.New(s)
+	}
+
+	back := dom.NewButton(locale.T("Back to Settings"))
+	back.OnClick(func(dom.Event) {
+		go Visit(CP)
+	})
+	hp.actions.AppendChild(back)
+	clearButton := dom.NewButton(locale.T("Clear History"))
+	clearButton.OnClick(func(dom.Event) {
+		if hp.history != nil {
+			hp.history.Clear()
+		}
+		hp.render()
+	})
+	hp.actions.AppendChild(clearButton)
+	return hp
+}
+
+// Show renders the stored history and displays the page
+func (hp *HistoryPage) Show() {
+	hp.render()
+	hp.page.Show()
+}
+
+// Record stores the given Record, if localStorage is available
+func (hp *HistoryPage) Record(r history.Record) {
+	if hp.history == nil {
+		return
+	}
+	if err := hp.history.Add(r); err != nil {
+		EP.Print(locale.Error(err))
+	}
+}
+
+// keystrokes returns the analysis.Stats per character of the latest
+// adaptiveGames of the given type
+func (hp *HistoryPage) keystrokes(t api.StreamSourceType) map[rune]analysis.Stats {
+	if hp.history == nil {
+		return nil
+	}
+	records, err := hp.history.Records()
+	if err != nil {
+		return nil
+	}
+	return history.Keystrokes(records, t, adaptiveGames)
+}
+
+func (hp *HistoryPage) render() {
+	var records []history.Record
+	if hp.history != nil {
+		if r, err := hp.history.Records(); err == nil {
+			records = r
+		}
+	}
+	if len(records) == 0 {
+		hp.charts.SetInnerHTML(`<span class="none">` + locale.T("no games played yet") + `</span>`)
+		hp.bests.SetInnerHTML("")
+		return
+	}
+	hp.charts.SetInnerHTML(progressCharts(history.Days(records)))
+	hp.bests.SetInnerHTML(personalBests(history.PersonalBests(records)))
+}
+
+// progressCharts draws the average words per minute and accuracy per day
+func progressCharts(days []history.Day) string {
+	if len(days) < 2 {
+		d := days[0]
+		return `<span class="name">` + locale.T("%s: %d games, %.0f words per minute, %.2f%% accuracy",
+			d.Date.Format("2006-01-02"), d.Games, d.NetWPM, 100*d.Accuracy) + `</span>`
+	}
+	wpm := make([]float64, len(days))
+	accuracy := make([]float64, len(days))
+	for i, d := range days {
+		wpm[i] = d.NetWPM
+		accuracy[i] = 100 * d.Accuracy
+	}
+	max := maximum(wpm)
+	if max == 0 {
+		max = 1
+	}
+	from := days[0].Date.Format("2006-01-02")
+	to := days[len(days)-1].Date.Format("2006-01-02")
+	return lineChart(max, wpm) +
+		`<span class="name">` + locale.T("words per minute per day from %s to %s (max %.0f)", from, to, max) + `</span>` +
+		lineChart(100, accuracy) +
+		`<span class="name">` + locale.T("accuracy per day from %s to %s", from, to) + `</span>`
+}
+
+// personalBests lists the best game of each configuration
+func personalBests(bests map[string]history.Record) string {
+	configurations := make([]string, 0, len(bests))
+	for c := range bests {
+		configurations = append(configurations, c)
+	}
+	sort.Strings(configurations)
+	var b strings.Builder
+	b.WriteString(`<span class="name">` + locale.T("personal bests") + `</span><table>`)
+	for _, c := range configurations {
+		r := bests[c]
+		b.WriteString(`<tr><td>` + html.EscapeString(locale.T(string(r.Type))) + `</td>`)
+		b.WriteString(`<td class="charset">` + html.EscapeString(strings.Replace(r.Charset, " ", "␣", -1)) + `</td>`)
+		b.WriteString(`<td class="value">` + locale.T("%.0f wpm", r.NetWPM) + `</td>`)
+		b.WriteString(`<td>` + locale.T("%.2f%%", 100*r.Accuracy) + `</td>`)
+		b.WriteString(`<td>` + r.Time.Local().Format("2006-01-02") + `</td></tr>`)
+	}
+	b.WriteString(`</table>`)
+	return b.String()
+}
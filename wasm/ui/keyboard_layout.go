@@ -0,0 +1,96 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+	"golang.org/x/text/language"
+)
+
+// layoutSelection holds the layout.Layout currently selected for a game-type
+// and notifies listeners about changes
+type layoutSelection struct {
+	current   *layout.Layout
+	listeners []func(*layout.Layout)
+}
+
+func newLayoutSelection(lang language.Tag) *layoutSelection {
+	return &layoutSelection{
+		current: layout.Default(lang),
+	}
+}
+
+func (s *layoutSelection) set(l *layout.Layout) {
+	if s.current == l {
+		return
+	}
+	s.current = l
+	for _, listener := range s.listeners {
+		listener(l)
+	}
+}
+
+func (s *layoutSelection) onChange(listener func(*layout.Layout)) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// keyboardLayout is the layout of the user's physical keyboard. It is chosen
+// independently of the interface's language and determines the characters of
+// the charset-options
+type keyboardLayout struct {
+	sst       api.StreamSourceType
+	selection *layoutSelection
+}
+
+func (k *keyboardLayout) choice() {}
+
+func (k *keyboardLayout) Name() string {
+	return locale.T("Keyboard Layout")
+}
+
+func (k *keyboardLayout) Description() string {
+	return locale.T("The layout of your keyboard.")
+}
+
+func (k *keyboardLayout) Options() []option {
+	options := make([]option, 0)
+	for _, l := range layout.All() {
+		l := l
+		options = append(options, &layoutoption{
+			modificatoroption: modificatoroption{
+				sst:              k.sst,
+				id:               "layout-" + l.ID,
+				description:      locale.T(l.Name),
+				enabledByDefault: l == k.selection.current,
+				stage:            config.Base,
+				// the layout has to be known, when the charset is built
+				priority: -1,
+				action: func(ssd *config.Description) {
+					ssd.Layout = l.ID
+				},
+			},
+			layout:    l,
+			selection: k.selection,
+		})
+	}
+	return options
+}
+
+// layoutoption selects a layout.Layout, while it is enabled
+type layoutoption struct {
+	modificatoroption
+	layout    *layout.Layout
+	selection *layoutSelection
+}
+
+func (l *layoutoption) OnEnable() func() {
+	enable := l.modificatoroption.OnEnable()
+	return func() {
+		enable()
+		l.selection.set(l.layout)
+	}
+}
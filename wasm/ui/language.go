@@ -0,0 +1,57 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"net/url"
+
+	"github.com/dennwc/dom"
+	"github.com/dennwc/dom/js"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+	"golang.org/x/text/language"
+)
+
+// requestedLanguage returns the language requested by the lang-parameter of
+// the page's location, or else the browser's preferred language
+func requestedLanguage() language.Tag {
+	if u, err := url.Parse(js.Get("window").Get("location").Get("href").String()); err == nil {
+		if l := u.Query().Get("lang"); l != "" {
+			if tag, err := language.Parse(l); err == nil {
+				return tag
+			}
+		}
+	}
+	if l := js.Get("navigator").Get("language"); !l.IsUndefined() && !l.IsNull() {
+		if tag, err := language.Parse(l.String()); err == nil {
+			return tag
+		}
+	}
+	return locale.Fallback
+}
+
+// newLanguageSwitcher creates a button for each of locale.Languages, which
+// reloads the given location in the respective language. The current language's
+// button is active
+func newLanguageSwitcher(location *url.URL) *dom.Element {
+	e := dom.NewElement("div")
+	for _, l := range locale.Languages {
+		l := l
+		b := dom.NewButton(locale.Name(l))
+		if l == locale.Language() {
+			b.ClassList().Add("active")
+		}
+		b.OnClick(func(dom.Event) {
+			if l == locale.Language() {
+				return
+			}
+			u := *location
+			q := u.Query()
+			q.Set("lang", l.String())
+			u.RawQuery = q.Encode()
+			js.Get("window").Get("location").Set("href", u.String())
+		})
+		e.AppendChild(b)
+	}
+	return e
+}
@@ -0,0 +1,170 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"html"
+	"strings"
+
+	"github.com/dennwc/dom"
+	com "github.com/theMomax/notypo-frontend/wasm/communication"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// LobbyRequestType identifies what the user wants to do in the lobby
+type LobbyRequestType int
+
+// lobby request types
+const (
+	// CreateRoom creates a new room hosted by the user
+	CreateRoom LobbyRequestType = iota
+	// JoinRoom joins the room identified by the request's RoomID
+	JoinRoom
+	// StartRace starts the race in the user's room
+	StartRace
+	// LeaveLobby returns to the config-page
+	LeaveLobby
+)
+
+// LobbyRequest is an action requested by the user on the lobby-page
+type LobbyRequest struct {
+	Type   LobbyRequestType
+	Player string
+	RoomID string
+}
+
+// LobbyPage represents the page, where multiplayer-races are arranged
+type LobbyPage struct {
+	page
+	join     *dom.Element
+	room     *dom.Element
+	status   *dom.Element
+	name     *dom.Input
+	code     *dom.Input
+	start    *dom.Button
+	requests chan LobbyRequest
+}
+
+// initLobbyPage initializes the page, where multiplayer-races are arranged
+func initLobbyPage() *LobbyPage {
+	lp := &LobbyPage{
+		page:     initPage("lobby"),
+		join:     dom.Doc.GetElementById("lobby_join"),
+		room:     dom.Doc.GetElementById("lobby_room"),
+		status:   dom.Doc.GetElementById("lobby_status"),
+		name:     dom.NewInput("text"),
+		code:     dom.NewInput("text"),
+		start:    dom.NewButton(locale.T("Start Race")),
+		requests: make(chan LobbyRequest, 1),
+	}
+	lp.name.SetAttribute("placeholder", locale.T("Your Name"))
+	lp.code.SetAttribute("placeholder", locale.T("Room Code"))
+	create := dom.NewButton(locale.T("Create Room"))
+	create.OnClick(func(dom.Event) {
+		if name := lp.player(); name != "" {
+			lp.request(LobbyRequest{Type: CreateRoom, Player: name})
+		}
+	})
+	join := dom.NewButton(locale.T("Join Room"))
+	join.OnClick(func(dom.Event) {
+		if name := lp.player(); name != "" {
+			lp.request(LobbyRequest{Type: JoinRoom, Player: name, RoomID: strings.TrimSpace(lp.code.Value())})
+		}
+	})
+	lp.join.AppendChild(lp.name)
+	lp.join.AppendChild(create)
+	lp.join.AppendChild(lp.code)
+	lp.join.AppendChild(join)
+
+	lp.start.OnClick(func(dom.Event) {
+		lp.request(LobbyRequest{Type: StartRace})
+	})
+	leave := dom.NewButton(locale.T("Leave"))
+	leave.OnClick(func(dom.Event) {
+		lp.request(LobbyRequest{Type: LeaveLobby})
+	})
+	actions := dom.Doc.GetElementById("lobby_actions")
+	actions.AppendChild(lp.start)
+	actions.AppendChild(leave)
+	return lp
+}
+
+// Requests returns the stream of the user's LobbyRequests
+func (lp *LobbyPage) Requests() <-chan LobbyRequest {
+	return lp.requests
+}
+
+// Reset shows the form for creating or joining a room
+func (lp *LobbyPage) Reset() {
+	// discard clicks, that happened before the lobby was entered
+	select {
+	case <-lp.requests:
+	default:
+	}
+	lp.join.ClassList().Remove("hidden")
+	lp.room.SetInnerHTML("")
+	lp.status.SetInnerHTML("")
+	lp.start.ClassList().Add("hidden")
+}
+
+// ShowRoom displays the given Room as seen by the given player
+func (lp *LobbyPage) ShowRoom(r *com.Room, self string) {
+	lp.join.ClassList().Add("hidden")
+	var b strings.Builder
+	b.WriteString(`<span class="name">` + locale.T("Room Code") + `</span><span class="code">` + html.EscapeString(r.ID) + `</span>`)
+	b.WriteString(standings(r.Players, self, r.Host))
+	lp.room.SetInnerHTML(b.String())
+	if r.Host == self && !r.Running {
+		lp.start.ClassList().Remove("hidden")
+		lp.status.SetInnerHTML("")
+	} else {
+		lp.start.ClassList().Add("hidden")
+		lp.status.SetInnerHTML(locale.T("waiting for the host to start the race"))
+	}
+}
+
+// Print displays the given message as the lobby's status
+func (lp *LobbyPage) Print(message string) {
+	lp.status.SetInnerHTML(html.EscapeString(message))
+}
+
+// player returns the name entered by the user. If there is none, the user is
+// asked to enter one
+func (lp *LobbyPage) player() string {
+	name := strings.TrimSpace(lp.name.Value())
+	if name == "" {
+		lp.Print(locale.T("Please enter your name."))
+	}
+	return name
+}
+
+func (lp *LobbyPage) request(r LobbyRequest) {
+	select {
+	case lp.requests <- r:
+	default:
+	}
+}
+
+// standings lists the given players in order. The entries of the given player
+// and host are marked
+func standings(players []com.Player, self, host string) string {
+	var b strings.Builder
+	b.WriteString(`<ol class="standings">`)
+	for _, p := range players {
+		var class []string
+		if p.Name == self {
+			class = append(class, "self")
+		}
+		if p.Eliminated {
+			class = append(class, "eliminated")
+		}
+		b.WriteString(`<li class="` + strings.Join(class, " ") + `"><span class="player">` + html.EscapeString(p.Name) + `</span>`)
+		if p.Name == host {
+			b.WriteString(`<span class="host">` + locale.T("host") + `</span>`)
+		}
+		b.WriteString(`<span class="value">` + locale.T("%.0f wpm", p.Progress.NetWPM) + `</span></li>`)
+	}
+	b.WriteString(`</ol>`)
+	return b.String()
+}
@@ -0,0 +1,241 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"html"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dennwc/dom"
+	"github.com/dennwc/dom/js"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/game"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// Choice is the user's decision on how to continue after a game
+type Choice int
+
+// choices
+const (
+	// Retry repeats the game with the same text
+	Retry Choice = iota
+	// RaceGhost repeats the game with the same text racing against the best run
+	// on it
+	RaceGhost
+	// NewText starts a new game with the same configuration
+	NewText
+	// Back returns to the config-page
+	Back
+	// Replay plays back the game at its original speed
+	Replay
+	// FastReplay plays back the game faster than it was played
+	FastReplay
+)
+
+// mostMissed is the maximum amount of characters listed as most missed
+const mostMissed = 5
+
+// chartPoints is the maximum amount of points in the speed-over-time-chart
+const chartPoints = 30
+
+// ResultsPage represents the page, which summarizes a finished game
+type ResultsPage struct {
+	page
+	stats   *dom.Element
+	missed  *dom.Element
+	chart   *dom.Element
+	heatmap *dom.Element
+	actions *dom.Element
+	choice  chan Choice
+	// recording is the game presented last
+	recording *game.Recording
+}
+
+// initResultsPage initializes the page, which summarizes a finished game
+func initResultsPage() *ResultsPage {
+	rp := &ResultsPage{
+		page:    initPage("results"),
+		stats:   dom.Doc.GetElementById("results_stats"),
+		missed:  dom.Doc.GetElementById("results_missed"),
+		chart:   dom.Doc.GetElementById("results_chart"),
+		heatmap: dom.Doc.GetElementById("results_heatmap"),
+		actions: dom.Doc.GetElementById("results_actions"),
+		choice:  make(chan Choice, 1),
+	}
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("Retry Same Text"), Retry))
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("Race Your Ghost"), RaceGhost))
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("New Text"), NewText))
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("Watch Replay"), Replay))
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("Fast Replay"), FastReplay))
+	if u, err := url.Parse(js.Get("window").Get("location").Get("href").String()); err == nil {
+		share := dom.NewButton(locale.T("Copy Replay Link"))
+		share.OnClick(func(dom.Event) {
+			if rp.recording != nil {
+				copyToClipboard(replayURL(u, locale.Language(), rp.recording))
+			}
+		})
+		rp.actions.AppendChild(share)
+	}
+	rp.actions.AppendChild(rp.newChoiceButton(locale.T("Back to Settings"), Back))
+	return rp
+}
+
+// Present displays the given results and blocks until the user decides how to
+// continue. The given Recording can be shared as a link
+func (rp *ResultsPage) Present(s comparison.Statistics, r comparison.Rates, a *analysis.Analysis, l *layout.Layout, rec *game.Recording) Choice {
+	// discard clicks, that happened before the results were presented
+	select {
+	case <-rp.choice:
+	default:
+	}
+	rp.stats.SetInnerHTML(stat(locale.T("%.0f", r.NetWPM()), locale.T("net words per minute")) +
+		stat(locale.T("%.0f", r.GrossWPM()), locale.T("gross words per minute")) +
+		stat(locale.T("%.2f%%", 100*r.Accuracy()), locale.T("accuracy")) +
+		stat(locale.T("%.2f%%", 100*s.FailureRate()), locale.T("failure rate")))
+	rp.missed.SetInnerHTML(missedCharacters(s.Misses()))
+	rp.chart.SetInnerHTML(speedChart(s, r.Elapsed()))
+	rp.heatmap.SetInnerHTML(heatmap(a, l) + fingers(a.Fingers(l)))
+	rp.recording = rec
+	return <-rp.choice
+}
+
+func (rp *ResultsPage) newChoiceButton(description string, c Choice) *dom.Button {
+	b := dom.NewButton(description)
+	b.OnClick(func(dom.Event) {
+		select {
+		case rp.choice <- c:
+		default:
+		}
+	})
+	return b
+}
+
+func stat(value, description string) string {
+	return `<div class="stat"><span class="value">` + value + `</span><span>` + description + `</span></div>`
+}
+
+// missedCharacters lists the characters, that were missed most often
+func missedCharacters(misses map[rune]int) string {
+	if len(misses) == 0 {
+		return `<span class="none">` + locale.T("no misses") + `</span>`
+	}
+	runes := make([]rune, 0, len(misses))
+	for r := range misses {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool {
+		if misses[runes[i]] != misses[runes[j]] {
+			return misses[runes[i]] > misses[runes[j]]
+		}
+		return runes[i] < runes[j]
+	})
+	if len(runes) > mostMissed {
+		runes = runes[:mostMissed]
+	}
+	var b strings.Builder
+	b.WriteString(`<span class="name">` + locale.T("most missed") + `</span>`)
+	for _, r := range runes {
+		s := string(r)
+		if r == ' ' {
+			s = "&#9251;"
+		} else {
+			s = html.EscapeString(s)
+		}
+		b.WriteString(`<span class="missed"><span class="character">` + s + `</span>` + strconv.Itoa(misses[r]) + `&times;</span>`)
+	}
+	return b.String()
+}
+
+// speedChart draws the words per minute over the given duration as svg
+func speedChart(s comparison.Statistics, elapsed time.Duration) string {
+	interval := time.Second
+	if elapsed > chartPoints*interval {
+		interval = (elapsed/chartPoints + time.Second - 1).Truncate(time.Second)
+	}
+	progression := s.Progression(interval)
+	if len(progression) < 2 {
+		return ""
+	}
+	wpm := make([]float64, len(progression))
+	for i, cpm := range progression {
+		wpm[i] = cpm / comparison.CharactersPerWord
+	}
+	max := maximum(wpm)
+	if max == 0 {
+		return ""
+	}
+	return lineChart(max, wpm) +
+		`<span class="name">` + locale.T("words per minute over time (max %.0f)", max) + `</span>`
+}
+
+// heatmap draws the given Layout with each key colored by its miss rate. The
+// characters of all of a key's levels are included
+func heatmap(a *analysis.Analysis, l *layout.Layout) string {
+	var b strings.Builder
+	b.WriteString(`<span class="name">` + locale.T("misses per key") + `</span><div class="keyboard">`)
+	for _, row := range l.Rows {
+		b.WriteString(`<div class="row">`)
+		for _, key := range row {
+			var s analysis.Stats
+			for i, r := range key.Levels {
+				if r != 0 && (i == 0 || r != key.Rune()) {
+					s = s.Add(a.Character(r))
+				}
+			}
+			label := html.EscapeString(string(key.Rune()))
+			class := "key"
+			if key.Rune() == ' ' {
+				label = "&nbsp;"
+				class += " space"
+			}
+			style := ""
+			title := locale.T("not typed")
+			if s.Strokes > 0 {
+				style = ` style="background-color: rgba(241, 89, 70, ` + strconv.FormatFloat(s.MissRate(), 'f', 2, 64) + `)"`
+				title = locale.T("%d of %d missed, %dms average latency", s.Misses, s.Strokes, int64(s.MeanLatency()/time.Millisecond))
+			} else {
+				class += " unused"
+			}
+			b.WriteString(`<span class="` + class + `"` + style + ` title="` + title + `">` + label + `</span>`)
+		}
+		b.WriteString(`</div>`)
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+// fingers lists the miss rate and latency of each used finger. The finger with
+// the highest miss rate is marked as the weakest
+func fingers(stats map[layout.Finger]analysis.Stats) string {
+	weakest := layout.Finger(-1)
+	for f := layout.LeftLittle; f <= layout.RightLittle; f++ {
+		s, ok := stats[f]
+		if ok && s.Misses > 0 && (weakest < 0 || s.MissRate() > stats[weakest].MissRate()) {
+			weakest = f
+		}
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="fingers">`)
+	for f := layout.LeftLittle; f <= layout.RightLittle; f++ {
+		s, ok := stats[f]
+		if !ok {
+			continue
+		}
+		class := "finger"
+		if f == weakest {
+			class += " weakest"
+		}
+		b.WriteString(`<div class="` + class + `"><span class="value">` + locale.T("%.1f%%", 100*s.MissRate()) +
+			`</span><span>` + locale.T(f.String()) + `</span><span>` + locale.T("%dms", int64(s.MeanLatency()/time.Millisecond)) + `</span></div>`)
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
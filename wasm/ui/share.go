@@ -0,0 +1,83 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"encoding/base64"
+	"net/url"
+
+	"github.com/dennwc/dom"
+	"github.com/dennwc/dom/js"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/errors"
+	"github.com/theMomax/notypo-frontend/wasm/game"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+	"golang.org/x/text/language"
+)
+
+// newShareButton creates a button, which copies a link to the clipboard. The
+// link points to the given location and contains config.Game's complete State
+// as well as the given language, so it opens the exact same configuration
+func newShareButton(location *url.URL, lang language.Tag) *dom.Button {
+	b := dom.NewButton(locale.T("Copy Link"))
+	b.OnClick(func(dom.Event) {
+		copyToClipboard(shareURL(location, lang))
+	})
+	return b
+}
+
+// shareURL returns the given location with its query replaced by config.Game's
+// State and the given language
+func shareURL(location *url.URL, lang language.Tag) string {
+	q := config.Game.State().Query()
+	q.Set("lang", lang.String())
+	u := *location
+	u.RawQuery = q.Encode()
+	u.Fragment = ""
+	return u.String()
+}
+
+// replayParameter is the query-parameter holding a shared game.Recording
+const replayParameter = "replay"
+
+// replayURL returns the given location with its query replaced by the given
+// Recording and language, so it plays back the Recording
+func replayURL(location *url.URL, lang language.Tag, r *game.Recording) string {
+	q := url.Values{}
+	q.Set(replayParameter, base64.RawURLEncoding.EncodeToString([]byte(r.Encode())))
+	q.Set("lang", lang.String())
+	u := *location
+	u.RawQuery = q.Encode()
+	u.Fragment = ""
+	return u.String()
+}
+
+// RequestedReplay returns the game.Recording shared via the page's location.
+// If there is none, nil is returned
+func RequestedReplay() (*game.Recording, errors.Error) {
+	u, err := url.Parse(js.Get("window").Get("location").Get("href").String())
+	if err != nil {
+		return nil, nil
+	}
+	encoded := u.Query().Get(replayParameter)
+	if encoded == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, game.ErrIllegalRecording.Append(err.Error())
+	}
+	return game.DecodeRecording(string(data))
+}
+
+// copyToClipboard writes the given text to the clipboard. If the clipboard is
+// not accessible, the text is displayed to be copied manually
+func copyToClipboard(text string) {
+	clipboard := js.Get("navigator").Get("clipboard")
+	if clipboard.IsUndefined() || clipboard.IsNull() {
+		js.Get("window").Call("prompt", locale.T("Copy this link:"), text)
+		return
+	}
+	clipboard.Call("writeText", text)
+}
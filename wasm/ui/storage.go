@@ -0,0 +1,40 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"github.com/dennwc/dom/storage"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+)
+
+// storageKeyState is the localStorage-key the config.State is persisted under
+const storageKeyState = "notypo.config"
+
+// loadState restores config.Game's State from localStorage. Invalid or
+// outdated states are discarded
+func loadState() {
+	s := storage.Local()
+	if s == nil {
+		return
+	}
+	data, ok := s.GetItem(storageKeyState)
+	if !ok {
+		return
+	}
+	state, err := config.DecodeState([]byte(data))
+	if err != nil {
+		s.RemoveItem(storageKeyState)
+		return
+	}
+	config.Game.Restore(state)
+}
+
+// saveState persists config.Game's State to localStorage
+func saveState() {
+	s := storage.Local()
+	if s == nil {
+		return
+	}
+	s.SetItem(storageKeyState, string(config.Game.State().Encode()))
+}
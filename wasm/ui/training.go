@@ -0,0 +1,46 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"github.com/theMomax/notypo-backend/api"
+	"github.com/theMomax/notypo-frontend/wasm/analysis"
+	"github.com/theMomax/notypo-frontend/wasm/config"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// adaptiveGames is the amount of previous games considered by the adaptive
+// training. As older games are dropped, the weights rebalance, when the user
+// improves
+const adaptiveGames = 5
+
+type training struct {
+	sst api.StreamSourceType
+}
+
+func (t *training) Name() string {
+	return locale.T("Training")
+}
+
+func (t *training) Description() string {
+	return locale.T("How the characters are chosen.")
+}
+
+func (t *training) Options() []option {
+	return []option{
+		&modificatoroption{
+			sst:         t.sst,
+			id:          "adaptive",
+			description: locale.T("Adaptive: Practice Weak Characters"),
+			stage:       config.Final,
+			action: func(ssd *config.Description) {
+				charset := make([]rune, len(ssd.Charset))
+				for i, c := range ssd.Charset {
+					charset[i] = c.Rune()
+				}
+				ssd.Weights = analysis.Weights(HP.keystrokes(t.sst), charset)
+			},
+		},
+	}
+}
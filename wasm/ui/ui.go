@@ -1,11 +1,19 @@
+//go:build js && wasm
+// +build js,wasm
+
 // Package ui provides helper-functions for DOM-manipulation
 package ui
 
+import "github.com/theMomax/notypo-frontend/wasm/locale"
+
 // shortcuts to the html-pages
 var (
 	LD page
 	CP *ConfigPage
 	GP *GamePage
+	RP *ResultsPage
+	HP *HistoryPage
+	LP *LobbyPage
 	EP *ErrorPage
 )
 
@@ -14,7 +22,8 @@ var (
 )
 
 func init() {
-	pages = make([]page, 0, 4)
+	locale.SetLanguage(requestedLanguage())
+	pages = make([]page, 0, 7)
 	EP = initErrorPage()
 	pages = append(pages, EP)
 	LD = initPage("loading")
@@ -23,6 +32,12 @@ func init() {
 	pages = append(pages, CP)
 	GP = initGamePage()
 	pages = append(pages, GP)
+	RP = initResultsPage()
+	pages = append(pages, RP)
+	HP = initHistoryPage()
+	pages = append(pages, HP)
+	LP = initLobbyPage()
+	pages = append(pages, LP)
 
 	Visit(CP)
 }
@@ -43,3 +58,9 @@ func Visit(target page) {
 func OnPlay(callback func()) {
 	CP.registerOnPlay(callback)
 }
+
+// OnMultiplayer registers a callback function, which is called, when the user
+// hits the multiplayer-button in the config-page
+func OnMultiplayer(callback func()) {
+	CP.registerOnMultiplayer(callback)
+}
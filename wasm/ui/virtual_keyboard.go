@@ -0,0 +1,160 @@
+//go:build js && wasm
+// +build js,wasm
+
+package ui
+
+import (
+	"html"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dennwc/dom"
+	"github.com/theMomax/notypo-frontend/wasm/comparison"
+	"github.com/theMomax/notypo-frontend/wasm/layout"
+	"github.com/theMomax/notypo-frontend/wasm/locale"
+)
+
+// missFlash is how long the key actually pressed is marked after a miss
+const missFlash = 300 * time.Millisecond
+
+// virtualKeyboard draws a layout.Layout and highlights the key of the next
+// expected character in the color of the finger, that should press it. It is
+// safe for concurrent use
+type virtualKeyboard struct {
+	mutex   sync.Mutex
+	element *dom.Element
+	layout  *layout.Layout
+	keys    [][]*dom.Element
+	levels  map[layout.Level]*dom.Element
+	// model holds the model-text received so far
+	model []rune
+	// position is the index of the next expected character within model
+	position int
+	next     *dom.Element
+	level    *dom.Element
+}
+
+func newVirtualKeyboard(element *dom.Element) *virtualKeyboard {
+	return &virtualKeyboard{
+		element: element,
+	}
+}
+
+// SetLayout draws the given Layout and resets the keyboard's state
+func (v *virtualKeyboard) SetLayout(l *layout.Layout) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.element.SetInnerHTML("")
+	v.layout = l
+	v.keys = make([][]*dom.Element, len(l.Rows))
+	for i, row := range l.Rows {
+		r := dom.NewElement("div")
+		r.ClassList().Add("row")
+		v.keys[i] = make([]*dom.Element, len(row))
+		for j, k := range row {
+			key := dom.NewElement("span")
+			key.ClassList().Add("key")
+			key.ClassList().Add("finger-" + strconv.Itoa(int(k.Finger)))
+			if k.Rune() == ' ' {
+				key.ClassList().Add("space")
+				key.SetInnerHTML("&nbsp;")
+			} else {
+				key.SetInnerHTML(html.EscapeString(string(k.Rune())))
+			}
+			r.AppendChild(key)
+			v.keys[i][j] = key
+		}
+		v.element.AppendChild(r)
+	}
+	modifiers := dom.NewElement("div")
+	modifiers.ClassList().Add("row")
+	v.levels = map[layout.Level]*dom.Element{
+		layout.Shift: modifier(locale.T("Shift")),
+		layout.AltGr: modifier(locale.T("AltGr")),
+	}
+	modifiers.AppendChild(v.levels[layout.Shift])
+	modifiers.AppendChild(v.levels[layout.AltGr])
+	v.element.AppendChild(modifiers)
+	v.model = v.model[:0]
+	v.position = 0
+	v.next = nil
+	v.level = nil
+}
+
+// Append adds the given Character to the model-text
+func (v *virtualKeyboard) Append(c comparison.Character) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.model = append(v.model, c.Rune())
+	if len(v.model) == v.position+1 {
+		v.hint()
+	}
+}
+
+// Press moves the hint according to the given Modification. If the
+// Modification is a miss, the key actually pressed is marked for a moment
+func (v *virtualKeyboard) Press(m comparison.Modification) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if m.Deletion() {
+		v.position = m.Position()
+	} else {
+		v.position = m.Position() + 1
+		if !m.Correct() {
+			v.flash(m.Rune())
+		}
+	}
+	v.hint()
+}
+
+// hint highlights the key of the character at position. The caller must hold
+// the mutex
+func (v *virtualKeyboard) hint() {
+	if v.next != nil {
+		v.next.ClassList().Remove("next")
+		v.next = nil
+	}
+	if v.level != nil {
+		v.level.ClassList().Remove("next")
+		v.level = nil
+	}
+	if v.layout == nil || v.position >= len(v.model) {
+		return
+	}
+	k, level, ok := v.layout.Key(v.model[v.position])
+	if !ok {
+		return
+	}
+	v.next = v.keys[k.Row][k.Column]
+	v.next.ClassList().Add("next")
+	if m, ok := v.levels[level]; ok {
+		v.level = m
+		v.level.ClassList().Add("next")
+	}
+}
+
+// flash marks the key, which produces the given rune, for missFlash. The
+// caller must hold the mutex
+func (v *virtualKeyboard) flash(r rune) {
+	if v.layout == nil {
+		return
+	}
+	k, _, ok := v.layout.Key(r)
+	if !ok {
+		return
+	}
+	key := v.keys[k.Row][k.Column]
+	key.ClassList().Add("missed")
+	time.AfterFunc(missFlash, func() {
+		key.ClassList().Remove("missed")
+	})
+}
+
+func modifier(name string) *dom.Element {
+	m := dom.NewElement("span")
+	m.ClassList().Add("key")
+	m.ClassList().Add("modifier")
+	m.SetInnerHTML(name)
+	return m
+}